@@ -21,6 +21,11 @@ type (
 		Node
 		Expression()
 	}
+
+	TypeExpr interface {
+		Node
+		TypeExpr()
+	}
 )
 
 type (
@@ -53,6 +58,7 @@ type (
 	LetStatement struct {
 		Token     token.Token
 		Ident     *Identifier
+		Type      TypeExpr // optional type annotation
 		InitValue Expression
 	}
 
@@ -112,6 +118,8 @@ type (
 	FunctionLiteral struct {
 		Token      token.Token
 		Parameters []*Identifier
+		ParamTypes []TypeExpr // optional annotations, parallel to Parameters
+		ReturnType TypeExpr   // optional return type annotation
 		Body       *BlockStatement
 	}
 
@@ -155,6 +163,35 @@ type (
 	}
 )
 
+type (
+	NamedType struct {
+		Token token.Token
+		Name  string // int, float, string, bool, null, any, function
+	}
+
+	ArrayType struct {
+		Token token.Token // '[' token
+		Elem  TypeExpr
+	}
+
+	MapType struct {
+		Token token.Token // '{' token
+		Key   TypeExpr
+		Value TypeExpr
+	}
+
+	UnionType struct {
+		Token token.Token // first '|' token
+		Types []TypeExpr
+	}
+
+	FunctionType struct {
+		Token      token.Token // `fn` keyword
+		Params     []TypeExpr
+		ReturnType TypeExpr
+	}
+)
+
 func (p *Program) String() string {
 	var out string
 
@@ -182,11 +219,16 @@ func (bs *BlockStatement) String() string {
 func (bs *BlockStatement) Statement() {}
 
 func (ls *LetStatement) String() string {
+	name := ls.Ident.Value
+	if ls.Type != nil {
+		name += ": " + ls.Type.String()
+	}
+
 	if ls.InitValue != nil {
-		return fmt.Sprintf("let %s = %s;", ls.Ident.Value, ls.InitValue)
+		return fmt.Sprintf("let %s = %s;", name, ls.InitValue)
 	}
 
-	return fmt.Sprintf("let %s", ls.Ident.Value)
+	return fmt.Sprintf("let %s", name)
 }
 
 func (ls *LetStatement) Location() token.SrcLoc {
@@ -224,16 +266,7 @@ func (js *JumpStatement) Location() token.SrcLoc {
 func (js *JumpStatement) Statement() {}
 
 func (fs *FunctionStatement) String() string {
-	var params string
-	for i, param := range fs.Value.Parameters {
-		if i == 0 {
-			params += param.String()
-		} else {
-			params += ", " + param.String()
-		}
-	}
-
-	return fmt.Sprintf("fn %s(%s) %s", fs.Ident, params, fs.Value.Body)
+	return fmt.Sprintf("fn %s%s %s", fs.Ident, fs.Value.signature(), fs.Value.Body)
 }
 
 func (fs *FunctionStatement) Location() token.SrcLoc {
@@ -357,15 +390,28 @@ func (ie *IndexExpression) Location() token.SrcLoc {
 func (ie *IndexExpression) Expression() {}
 
 func (fl *FunctionLiteral) String() string {
+	return fmt.Sprintf("fn %s %s", fl.signature(), fl.Body)
+}
+
+// signature returns the parameter list along with any type annotations
+// and the return type if one was given, e.g. `(x: int, y) -> int`
+func (fl *FunctionLiteral) signature() string {
 	var params string
 	for i, param := range fl.Parameters {
-		if i == 0 {
-			params += param.String()
-		} else {
-			params += ", " + param.String()
+		if i != 0 {
+			params += ", "
 		}
+		params += param.String()
+		if i < len(fl.ParamTypes) && fl.ParamTypes[i] != nil {
+			params += ": " + fl.ParamTypes[i].String()
+		}
+	}
+
+	if fl.ReturnType != nil {
+		return fmt.Sprintf("(%s) -> %s", params, fl.ReturnType)
 	}
-	return fmt.Sprintf("fn (%s) %s", params, fl.Body)
+
+	return fmt.Sprintf("(%s)", params)
 }
 
 func (fl *FunctionLiteral) Location() token.SrcLoc {
@@ -469,3 +515,75 @@ func (nl *NullLiteral) Location() token.SrcLoc {
 }
 
 func (nl *NullLiteral) Expression() {}
+
+func (nt *NamedType) String() string {
+	return nt.Name
+}
+
+func (nt *NamedType) Location() token.SrcLoc {
+	return nt.Token.Loc
+}
+
+func (nt *NamedType) TypeExpr() {}
+
+func (at *ArrayType) String() string {
+	return fmt.Sprintf("[%s]", at.Elem)
+}
+
+func (at *ArrayType) Location() token.SrcLoc {
+	return at.Token.Loc
+}
+
+func (at *ArrayType) TypeExpr() {}
+
+func (mt *MapType) String() string {
+	return fmt.Sprintf("{%s: %s}", mt.Key, mt.Value)
+}
+
+func (mt *MapType) Location() token.SrcLoc {
+	return mt.Token.Loc
+}
+
+func (mt *MapType) TypeExpr() {}
+
+func (ut *UnionType) String() string {
+	var out string
+	for i, typ := range ut.Types {
+		if i == 0 {
+			out += typ.String()
+		} else {
+			out += " | " + typ.String()
+		}
+	}
+
+	return out
+}
+
+func (ut *UnionType) Location() token.SrcLoc {
+	return ut.Token.Loc
+}
+
+func (ut *UnionType) TypeExpr() {}
+
+func (ft *FunctionType) String() string {
+	var params string
+	for i, param := range ft.Params {
+		if i == 0 {
+			params += param.String()
+		} else {
+			params += ", " + param.String()
+		}
+	}
+
+	if ft.ReturnType != nil {
+		return fmt.Sprintf("fn(%s) -> %s", params, ft.ReturnType)
+	}
+
+	return fmt.Sprintf("fn(%s)", params)
+}
+
+func (ft *FunctionType) Location() token.SrcLoc {
+	return ft.Token.Loc
+}
+
+func (ft *FunctionType) TypeExpr() {}
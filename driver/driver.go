@@ -4,24 +4,63 @@ import (
 	"RoLang/evaluator"
 	"RoLang/lexer"
 	"RoLang/parser"
+	"RoLang/typecheck"
 
 	"errors"
 	"fmt"
 	"os"
 )
 
-func Execute(file string, code string) {
+// Execute runs the code and returns the exit code for the process,
+// which is the code of a top level return or 1 if errors occurred
+// or the code does not fit in the 0 to 255 range of exit statuses
+func Execute(file string, code string, opts ...evaluator.Option) int {
 	lexer := lexer.New(file, code)
 	parser := parser.New(lexer)
-	evaluator := evaluator.New()
+	evaluator := evaluator.New(opts...)
 
 	program, errs := parser.Parse()
 	if len(errs) != 0 {
 		fmt.Fprintln(os.Stderr, errors.Join(errs...))
+		return 1
 	}
 
-	errs = evaluator.Evaluate(program)
+	exit, errs := evaluator.Evaluate(program)
 	if len(errs) != 0 {
 		fmt.Fprintln(os.Stderr, errors.Join(errs...))
+		return 1
 	}
+
+	if exit != nil {
+		// processes only report the low byte of the code, so
+		// other codes would be mistaken for something else
+		if exit.Code < 0 || exit.Code > 255 {
+			return 1
+		}
+		return int(exit.Code)
+	}
+
+	return 0
+}
+
+// Check statically type checks the code without running it
+// and reports whether the code is free of errors
+func Check(file string, code string) bool {
+	lexer := lexer.New(file, code)
+	parser := parser.New(lexer)
+	checker := typecheck.New()
+
+	program, errs := parser.Parse()
+	if len(errs) != 0 {
+		fmt.Fprintln(os.Stderr, errors.Join(errs...))
+		return false
+	}
+
+	errs = checker.Check(program)
+	if len(errs) != 0 {
+		fmt.Fprintln(os.Stderr, errors.Join(errs...))
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,138 @@
+package evaluator
+
+import (
+	"RoLang/evaluator/objects"
+
+	"cmp"
+	"fmt"
+)
+
+// arrays, maps and deques are compared by their elements. containers
+// can hold themselves, so the pairs being compared are remembered and
+// a pair met again further down counts as equal, or as neither smaller
+// nor greater, leaving the answer to the rest of the elements. as they
+// are the containers around the current one, their number is also how
+// deep the comparison recurses
+
+type pair struct {
+	left, right any
+}
+
+// marks a pair as being compared, seen tells whether it already was
+func visit(visiting map[pair]bool, left, right any) (map[pair]bool, bool, error) {
+	if visiting == nil {
+		visiting = map[pair]bool{}
+	}
+	p := pair{left, right}
+	if visiting[p] {
+		return visiting, true, nil
+	}
+	if len(visiting) >= objects.MaxNesting {
+		return visiting, false, fmt.Errorf("%w (%d levels)", objects.ErrNesting, objects.MaxNesting)
+	}
+	visiting[p] = true
+
+	return visiting, false, nil
+}
+
+func (e *Evaluator) equalLists(l, r any, left, right []any, visiting map[pair]bool) (bool, error) {
+	if len(left) != len(right) {
+		return false, nil
+	}
+	if l == r {
+		return true, nil
+	}
+	visiting, seen, err := visit(visiting, l, r)
+	if err != nil {
+		return false, err
+	} else if seen {
+		return true, nil
+	}
+	defer delete(visiting, pair{l, r})
+
+	for i := range left {
+		eq, err := e.equal(left[i], right[i], visiting)
+		if err != nil || !eq {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (e *Evaluator) equalMaps(l, r *objects.MapObject, visiting map[pair]bool) (bool, error) {
+	if l.Len() != r.Len() {
+		return false, nil
+	}
+	if l == r {
+		return true, nil
+	}
+	visiting, seen, err := visit(visiting, l, r)
+	if err != nil {
+		return false, err
+	} else if seen {
+		return true, nil
+	}
+	defer delete(visiting, pair{l, r})
+
+	for key, value := range l.All() {
+		other, ok := r.Get(key)
+		if !ok {
+			return false, nil
+		}
+		eq, err := e.equal(value, other, visiting)
+		if err != nil || !eq {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// compare orders two values the way `<` and `>` do
+func (e *Evaluator) compare(left, right any, visiting map[pair]bool) (int, error) {
+	if l, ok := left.(*objects.ArrayObject); ok {
+		if r, ok := right.(*objects.ArrayObject); ok {
+			return e.compareArrays(l, r, visiting)
+		}
+	}
+
+	less, err := e.evalLtOperator(left, right)
+	if err != nil {
+		return 0, err
+	}
+	if less.(bool) {
+		return -1, nil
+	}
+	greater, err := e.evalLtOperator(right, left)
+	if err != nil {
+		return 0, err
+	}
+	if greater.(bool) {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// arrays are ordered by their first differing element, an
+// array which is the start of the other one comes first
+func (e *Evaluator) compareArrays(l, r *objects.ArrayObject, visiting map[pair]bool) (int, error) {
+	if l == r {
+		return 0, nil
+	}
+	visiting, seen, err := visit(visiting, l, r)
+	if seen || err != nil {
+		return 0, err
+	}
+	defer delete(visiting, pair{l, r})
+
+	for i := range min(len(l.List), len(r.List)) {
+		order, err := e.compare(l.List[i], r.List[i], visiting)
+		if err != nil || order != 0 {
+			return order, err
+		}
+	}
+
+	return cmp.Compare(len(l.List), len(r.List)), nil
+}
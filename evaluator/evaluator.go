@@ -8,36 +8,189 @@ import (
 	"RoLang/stdlib/builtin"
 	"RoLang/stdlib/common"
 	"RoLang/stdlib/strings"
+	"RoLang/token"
 
+	"context"
+	"errors"
 	"fmt"
-	"maps"
-	"os"
+	"math/big"
+	"reflect"
 	"slices"
+	gostrings "strings"
 )
 
 type Evaluator struct {
 	errors   []error
 	env      *env.Environment
 	envStack []*env.Environment
+	globals  *env.Environment
 	stdlib   *stdlib.StdLib
+	config   stdlib.Config
+	// value of the last top level expression statement
+	result any
+	// value of the last expression statement at any depth
+	value any
+	// set by a top level return
+	exit *Exit
+	// check type annotations while evaluating
+	typeChecks bool
+	// report integer overflow instead of promoting to big integers
+	checkedOverflow bool
+	limits          limits
+	// set while a program or a call from Go runs
+	running bool
 }
 
-func New() *Evaluator {
-	return &Evaluator{
-		env:    env.New(nil),
-		stdlib: stdlib.New(),
+func New(opts ...Option) *Evaluator {
+	e := &Evaluator{
+		env: env.New(nil),
+		limits: limits{
+			ctx:      context.Background(),
+			maxDepth: defaultMaxCallDepth,
+		},
 	}
+	e.globals = e.env
+
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.config.Caller = callbacks{e}
+	e.config.Operators = callbacks{e}
+	e.config.Context = func() context.Context { return e.limits.ctx }
+	e.stdlib = stdlib.New(e.config)
+
+	return e
+}
+
+// Exit is the result of a top level return, which asks the
+// program running the script to end with the given code
+type Exit struct {
+	Code  int64
+	Value any
 }
 
-func (e *Evaluator) Evaluate(program *ast.Program) []error {
+// Evaluate runs the program and returns the exit requested by a
+// top level return, or nil if the program ran to the end
+func (e *Evaluator) Evaluate(program *ast.Program) (*Exit, []error) {
 	e.errors = nil
+	e.result = nil
+	e.exit = nil
+	e.limits.reset()
+
+	running := e.running
+	e.running = true
+	defer func() { e.running = running }()
+
+	err := e.evalProgram(program.Statements)
+	// os.exit unwinds with an error which ends the program like a return
+	var exit common.Exit
+	if errors.As(err, &exit) {
+		e.exit = &Exit{Code: exit.Code, Value: exit.Code}
+	} else if err != nil {
+		e.addError(jumpError(err))
+	}
+
+	return e.exit, e.errors
+}
+
+// EvaluateContext is like Evaluate but stops the program once
+// the context is cancelled or its deadline has passed
+func (e *Evaluator) EvaluateContext(ctx context.Context, program *ast.Program) (*Exit, []error) {
+	outer := e.limits.ctx
+	e.limits.ctx = ctx
+	defer func() { e.limits.ctx = outer }()
+
+	return e.Evaluate(program)
+}
+
+// Result returns the value of the last statement of the previous
+// `Evaluate` call, or nil when it was not an expression statement
+func (e *Evaluator) Result() any {
+	return e.result
+}
+
+// Define creates or overwrites a variable in the global scope
+func (e *Evaluator) Define(name string, value any) {
+	if !e.globals.Assign(name, value) {
+		e.globals.Set(name, value)
+	}
+}
+
+// Lookup returns the value of a variable in the global scope
+func (e *Evaluator) Lookup(name string) (any, bool) {
+	return e.globals.Get(name)
+}
+
+// Call invokes a RoLang function or a stdlib function with the given
+// arguments, errors raised while running it are returned. A function
+// ending the script with os.exit returns a common.Exit error.
+// A call from Go has the limits of a whole evaluation to itself, while
+// Go functions called by a running script share the script's limits
+func (e *Evaluator) Call(function any, args ...any) (retValue any, errValue error) {
+	if !e.running {
+		e.limits.reset()
+		e.running = true
+		defer func() { e.running = false }()
+	}
+	defer func() {
+		if err, ok := recover().(error); ok {
+			retValue, errValue = nil, fmt.Errorf("runtime error:%v", err)
+		}
+	}()
+
+	value, err := e.callFunction(function, args)
+	var exit common.Exit
+	if errors.As(err, &exit) {
+		return nil, exit
+	}
+
+	return value, err
+}
+
+// CallContext is like Call but stops the function once
+// the context is cancelled or its deadline has passed
+func (e *Evaluator) CallContext(ctx context.Context, function any, args ...any) (any, error) {
+	outer := e.limits.ctx
+	e.limits.ctx = ctx
+	defer func() { e.limits.ctx = outer }()
+
+	return e.Call(function, args...)
+}
+
+// callbacks lets stdlib functions call RoLang functions. unlike Call
+// it leaves panics to the handlers of the surrounding evaluation
+type callbacks struct {
+	e *Evaluator
+}
 
-	err := e.evalStatements(program.Statements)
+func (c callbacks) Call(function any, args ...any) (any, error) {
+	return c.e.callFunction(function, args)
+}
+
+func (c callbacks) Equal(left, right any) (bool, error) {
+	equal, err := c.e.evalEqOperator(left, right)
+	if err != nil {
+		return false, err
+	}
+
+	return equal.(bool), nil
+}
+
+func (c callbacks) Less(left, right any) (bool, error) {
+	less, err := c.e.evalLtOperator(left, right)
 	if err != nil {
-		e.addError(err)
+		return false, err
 	}
 
-	return e.errors
+	return less.(bool), nil
+}
+
+func (c callbacks) Compare(left, right any) (int, error) {
+	return c.e.compare(left, right, nil)
+}
+
+func (c callbacks) Add(left, right any) (any, error) {
+	return c.e.evalAddOperator(left, right)
 }
 
 // for block scopes it should just enclose the current environment
@@ -75,16 +228,16 @@ func (e *Evaluator) recoveryHandler() {
 	if err != nil {
 		switch v := err.(type) {
 		case objects.ReturnObject:
-			switch v := v.Value.(type) {
+			switch code := v.Value.(type) {
 			case int64:
-				os.Exit(int(v))
+				e.exit = &Exit{Code: code, Value: v.Value}
 			case nil:
-				os.Exit(0)
+				e.exit = &Exit{}
 			default:
 				e.addError(fmt.Errorf("can only return integer exit codes at top level"))
 			}
 		case error:
-			e.addError(fmt.Errorf("runtime error:%v", err))
+			e.addError(fmt.Errorf("runtime error:%w", v))
 		}
 	}
 }
@@ -94,14 +247,36 @@ func (e *Evaluator) errorDecorator(node ast.Node, err error) error {
 		if _, ok := err.(objects.JumpObject); ok {
 			return err
 		}
-		return fmt.Errorf("\n%s %s", node.Location(), err)
+		if trace, ok := err.(*traceError); ok {
+			trace.locs = append(trace.locs, node.Location())
+			return trace
+		}
+		return &traceError{locs: []token.SrcLoc{node.Location()}, err: err}
 	}
 
 	return nil
 }
 
-func (e *Evaluator) evalStatements(stmts []ast.Statement) error {
+// evaluates the top level statements, a return statement at the
+// top level unwinds up to here and ends the program
+func (e *Evaluator) evalProgram(stmts []ast.Statement) error {
 	defer e.recoveryHandler()
+
+	for _, stmt := range stmts {
+		if err := e.evalStatement(stmt); err != nil {
+			return err
+		}
+		// statements inside called functions do not give the result
+		e.result = nil
+		if _, ok := stmt.(*ast.ExpressionStatement); ok {
+			e.result = e.value
+		}
+	}
+
+	return nil
+}
+
+func (e *Evaluator) evalStatements(stmts []ast.Statement) error {
 	for _, stmt := range stmts {
 		err := e.evalStatement(stmt)
 		if err != nil {
@@ -115,7 +290,10 @@ func (e *Evaluator) evalStatements(stmts []ast.Statement) error {
 func (e *Evaluator) evalStatement(statement ast.Statement) error {
 	// error handler for statement panics
 	// used for adding source location to the error
-	var err error
+	err := e.limits.step()
+	if err != nil {
+		return e.errorDecorator(statement, err)
+	}
 
 	switch stmt := statement.(type) {
 	case *ast.LetStatement:
@@ -132,7 +310,7 @@ func (e *Evaluator) evalStatement(statement ast.Statement) error {
 		err = e.evalStatements(stmt.Statements)
 		// should pop out the current environment no matter what
 	case *ast.ExpressionStatement:
-		_, err = e.evalExpression(stmt.Expression)
+		e.value, err = e.evalExpression(stmt.Expression)
 	case *ast.LoopStatement:
 		err = e.evalLoopStatement(stmt)
 	case *ast.JumpStatement:
@@ -158,6 +336,10 @@ func (e *Evaluator) evalFunctionStatement(function *ast.FunctionStatement) error
 func (e *Evaluator) evalLoopStatement(loop *ast.LoopStatement) error {
 	cond := true
 	for {
+		if err := e.limits.checkContext(); err != nil {
+			return err
+		}
+
 		// should continue looping if there is no condition
 		// or check the condition repeatedly
 		if loop.Condition != nil {
@@ -166,7 +348,7 @@ func (e *Evaluator) evalLoopStatement(loop *ast.LoopStatement) error {
 				return err
 			}
 
-			cond = isTruthy(expr)
+			cond = common.IsTruthy(expr)
 		}
 		// condition evaluates to false
 		if !cond {
@@ -204,6 +386,10 @@ func (e *Evaluator) evalLetStatement(let *ast.LetStatement) error {
 	if err != nil {
 		return err
 	}
+	if e.typeChecks && let.Type != nil && !matchesType(init, let.Type) {
+		return fmt.Errorf("cannot use %s as type %s in let %s",
+			builtin.TypeStr(init), let.Type, name)
+	}
 	if !e.env.Set(name, init) {
 		return fmt.Errorf("variable %s already exists in current scope", name)
 	}
@@ -230,7 +416,7 @@ func (e *Evaluator) evalIfStatement(ifStmt *ast.IfStatement) error {
 		return err
 	}
 
-	if isTruthy(condition) {
+	if common.IsTruthy(condition) {
 		err := e.evalStatement(ifStmt.Then)
 		if err != nil {
 			return err
@@ -246,6 +432,10 @@ func (e *Evaluator) evalIfStatement(ifStmt *ast.IfStatement) error {
 }
 
 func (e *Evaluator) evalExpression(expression ast.Expression) (value any, err error) {
+	if err := e.limits.step(); err != nil {
+		return nil, e.errorDecorator(expression, err)
+	}
+
 	switch expr := expression.(type) {
 	case *ast.InfixExpression:
 		value, err = e.evalInfixExpression(expr)
@@ -284,6 +474,10 @@ func (e *Evaluator) evalExpression(expression ast.Expression) (value any, err er
 }
 
 func (e *Evaluator) evalArrayLiteral(expr *ast.ArrayLiteral) (*objects.ArrayObject, error) {
+	if err := e.limits.allocate(int64(len(expr.Elements))); err != nil {
+		return nil, err
+	}
+
 	arr := &objects.ArrayObject{}
 
 	for _, elem := range expr.Elements {
@@ -298,20 +492,25 @@ func (e *Evaluator) evalArrayLiteral(expr *ast.ArrayLiteral) (*objects.ArrayObje
 }
 
 func (e *Evaluator) evalMapLiteral(expr *ast.MapLiteral) (*objects.MapObject, error) {
-	mp := &objects.MapObject{
-		Map: make(map[any]any),
+	if err := e.limits.allocate(int64(len(expr.Elements))); err != nil {
+		return nil, err
 	}
 
+	mp := &objects.MapObject{}
+
 	for _, elem := range expr.Elements {
 		key, err := e.evalExpression(elem.Key)
 		if err != nil {
 			return nil, err
 		}
+		if err := builtin.CheckKey(key); err != nil {
+			return nil, err
+		}
 		val, err := e.evalExpression(elem.Value)
 		if err != nil {
 			return nil, err
 		}
-		mp.Map[key] = val
+		mp.Set(key, val)
 	}
 
 	return mp, nil
@@ -329,9 +528,13 @@ func (e *Evaluator) evalIndexExpression(expr *ast.IndexExpression) (any, error)
 		if err != nil {
 			return nil, err
 		}
+		if n, ok := right.(*big.Int); ok {
+			return nil, fmt.Errorf("index out of range [%s]", n)
+		}
+
 		index, ok := right.(int64)
 		if !ok {
-			return nil, fmt.Errorf("expect integer index, got=%s", builtin.TypeStr(index))
+			return nil, fmt.Errorf("expect integer index, got=%s", builtin.TypeStr(right))
 		}
 
 		if index >= int64(len(v.List)) || index < 0 {
@@ -339,24 +542,51 @@ func (e *Evaluator) evalIndexExpression(expr *ast.IndexExpression) (any, error)
 		}
 
 		return v.List[index], nil
+	case *objects.DequeObject:
+		right, err := e.evalExpression(expr.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		index, ok := right.(int64)
+		if !ok {
+			return nil, fmt.Errorf("expect integer index, got=%s", builtin.TypeStr(right))
+		}
+
+		elem, ok := v.At(int(index))
+		if !ok || int64(int(index)) != index {
+			return nil, fmt.Errorf("index out of range [%d]", index)
+		}
+
+		return elem, nil
+	case string:
+		right, err := e.evalExpression(expr.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		index, ok := right.(int64)
+		if !ok {
+			return nil, fmt.Errorf("expect integer index, got=%s", builtin.TypeStr(right))
+		}
+
+		// strings are indexed by code points, not bytes
+		char, ok := strings.At(v, index)
+		if !ok {
+			return nil, fmt.Errorf("index out of range [%d]", index)
+		}
+
+		return char, nil
 	case *objects.MapObject:
 		right, err := e.evalExpression(expr.Index)
 		if err != nil {
 			return nil, err
 		}
-		switch right.(type) {
-		case int64:
-			return v.Map[right], nil
-		case float64:
-			return v.Map[right], nil
-		case string:
-			return v.Map[right], nil
-		case bool:
-			return v.Map[right], nil
-		default:
-			return nil, fmt.Errorf("only int, float, string and bool is allowed as key. got=%s",
-				builtin.TypeStr(v))
+		if err := builtin.CheckKey(right); err != nil {
+			return nil, err
 		}
+		value, _ := v.Get(right)
+		return value, nil
 	}
 
 	return nil, fmt.Errorf("cannot index on type %s", builtin.TypeStr(left))
@@ -396,6 +626,14 @@ func (e *Evaluator) evalCallArgs(args []ast.Expression) ([]any, error) {
 func (e *Evaluator) callFunction(function any, args []any) (retValue any, errValue error) {
 	switch obj := function.(type) {
 	case objects.FuncObject:
+		if err := e.limits.checkContext(); err != nil {
+			return nil, err
+		}
+		if err := e.limits.enterCall(); err != nil {
+			return nil, err
+		}
+		defer e.limits.exitCall()
+
 		returnHandler := func() {
 			e.resetEnv()
 
@@ -406,6 +644,12 @@ func (e *Evaluator) callFunction(function any, args []any) (retValue any, errVal
 			case error:
 				errValue = val
 			}
+
+			returnType := obj.Function.ReturnType
+			if errValue == nil && e.typeChecks && returnType != nil && !matchesType(retValue, returnType) {
+				retValue, errValue = nil, fmt.Errorf("cannot return %s from function returning %s",
+					builtin.TypeStr(retValue), returnType)
+			}
 		}
 		defer returnHandler() // set return value or propagate error
 
@@ -419,6 +663,11 @@ func (e *Evaluator) callFunction(function any, args []any) (retValue any, errVal
 		}
 
 		for i, param := range function.Parameters {
+			if e.typeChecks && i < len(function.ParamTypes) && function.ParamTypes[i] != nil &&
+				!matchesType(args[i], function.ParamTypes[i]) {
+				return nil, fmt.Errorf("cannot use %s as %s in argument %d",
+					builtin.TypeStr(args[i]), function.ParamTypes[i], i+1)
+			}
 			if !e.env.Set(param.Value, args[i]) {
 				return nil, fmt.Errorf("redeclaration of variable %s", param.Value)
 			}
@@ -426,18 +675,48 @@ func (e *Evaluator) callFunction(function any, args []any) (retValue any, errVal
 
 		err := e.evalStatements(function.Body.Statements)
 		if err != nil {
-			return nil, err
+			// break and continue cannot leave the function body
+			return nil, jumpError(err)
 		}
 		// reaching here means function does not return any value
 		// in one of the control flow paths
 		return nil, nil
 	case common.Sanitizer:
-		return obj(args...)
+		before := elementCount(args...)
+		value, err := obj(args...)
+		if err != nil {
+			return nil, err
+		}
+
+		// stdlib functions may grow their arguments or build
+		// new containers, count whatever was added by the call
+		after := elementCount(args...)
+		switch value.(type) {
+		case *objects.ArrayObject, *objects.MapObject,
+			*objects.SetObject, *objects.DequeObject, *objects.HeapObject:
+			if !slices.Contains(args, value) {
+				after += elementCount(value)
+			}
+		}
+		if err := e.limits.allocate(after - before); err != nil {
+			return nil, err
+		}
+
+		return value, nil
 	default:
 		return nil, fmt.Errorf("not a callable %s", builtin.TypeStr(function))
 	}
 }
 
+// turns a break or continue that escaped every loop into an error
+func jumpError(err error) error {
+	if jump, ok := err.(objects.JumpObject); ok {
+		return fmt.Errorf("%s outside of a loop", jump)
+	}
+
+	return err
+}
+
 func (e *Evaluator) evalFunctionLiteral(expr *ast.FunctionLiteral) (objects.FuncObject, error) {
 	return objects.FuncObject{
 		Env:      e.env,
@@ -496,6 +775,12 @@ func (e *Evaluator) evalInfixExpression(expr *ast.InfixExpression) (any, error)
 			return nil, err
 		}
 		return !expr.(bool), nil
+	case "|":
+		return e.evalSetOperator("union", left, right)
+	case "&":
+		return e.evalSetOperator("intersection", left, right)
+	case "in":
+		return e.evalInOperator(left, right)
 	case "==":
 		return e.evalEqOperator(left, right)
 	case "!=":
@@ -534,7 +819,7 @@ func (e *Evaluator) evalAssignExpression(expr *ast.AssignExpression) (any, error
 
 			index, ok := indexExpr.(int64)
 			if !ok {
-				return nil, fmt.Errorf("expect integer index, got=%s", builtin.TypeStr(index))
+				return nil, fmt.Errorf("expect integer index, got=%s", builtin.TypeStr(indexExpr))
 			}
 
 			if index >= int64(len(v.List)) || index < 0 {
@@ -548,19 +833,15 @@ func (e *Evaluator) evalAssignExpression(expr *ast.AssignExpression) (any, error
 				return nil, err
 			}
 
-			switch index.(type) {
-			case int64:
-				v.Map[index] = right
-			case float64:
-				v.Map[index] = right
-			case string:
-				v.Map[index] = right
-			case bool:
-				v.Map[index] = right
-			default:
-				return nil, fmt.Errorf("only int, float, string and bool is allowed as key. got=%s",
-					builtin.TypeStr(v))
+			if err := builtin.CheckKey(index); err != nil {
+				return nil, err
 			}
+			if _, ok := v.Get(index); !ok {
+				if err := e.limits.allocate(1); err != nil {
+					return nil, err
+				}
+			}
+			v.Set(index, right)
 		}
 	}
 
@@ -572,7 +853,7 @@ func (e *Evaluator) evalModuleOperator(left, right any) (any, error) {
 	case *ast.Identifier:
 		switch r := right.(type) {
 		case *ast.Identifier:
-			return e.stdlib.GetModuleDispatcher(l.Value, r.Value)
+			return e.stdlib.GetMember(l.Value, r.Value)
 		default:
 			return nil, fmt.Errorf("expect identifier after dot operator found %s",
 				strings.From(r))
@@ -583,11 +864,13 @@ func (e *Evaluator) evalModuleOperator(left, right any) (any, error) {
 }
 
 func (e *Evaluator) evalAddOperator(left, right any) (any, error) {
+	left, right = widen(left, right)
+
 	switch l := left.(type) {
 	case int64:
 		switch r := right.(type) {
 		case int64:
-			return l + r, nil
+			return e.addInt(l, r)
 		case float64:
 			return float64(l) + r, nil
 		case string:
@@ -616,6 +899,18 @@ func (e *Evaluator) evalAddOperator(left, right any) (any, error) {
 			return strings.From(l) + strings.From(r), nil
 		case float64:
 			return strings.From(l) + strings.From(r), nil
+		case *big.Int:
+			return strings.From(l) + strings.From(r), nil
+		default:
+			return nil, fmt.Errorf("addition not supported for %s and %s",
+				builtin.TypeStr(l), builtin.TypeStr(r))
+		}
+	case *big.Int:
+		switch r := right.(type) {
+		case *big.Int:
+			return objects.NormalizeBig(new(big.Int).Add(l, r)), nil
+		case string:
+			return strings.From(l) + strings.From(r), nil
 		default:
 			return nil, fmt.Errorf("addition not supported for %s and %s",
 				builtin.TypeStr(l), builtin.TypeStr(r))
@@ -623,6 +918,9 @@ func (e *Evaluator) evalAddOperator(left, right any) (any, error) {
 	case *objects.ArrayObject:
 		switch r := right.(type) {
 		case *objects.ArrayObject:
+			if err := e.limits.allocate(l.Len() + r.Len()); err != nil {
+				return nil, err
+			}
 			return &objects.ArrayObject{
 				List: slices.Concat(l.List, r.List),
 			}, nil
@@ -633,11 +931,13 @@ func (e *Evaluator) evalAddOperator(left, right any) (any, error) {
 	case *objects.MapObject:
 		switch r := right.(type) {
 		case *objects.MapObject:
-			mapObj := &objects.MapObject{
-				Map: make(map[any]any),
+			mapObj := l.Clone()
+			for key, value := range r.All() {
+				mapObj.Set(key, value)
+			}
+			if err := e.limits.allocate(mapObj.Len()); err != nil {
+				return nil, err
 			}
-			maps.Copy(mapObj.Map, l.Map)
-			maps.Copy(mapObj.Map, r.Map)
 			return mapObj, nil
 		default:
 			return nil, fmt.Errorf("addition not supported for %s and %s",
@@ -649,11 +949,13 @@ func (e *Evaluator) evalAddOperator(left, right any) (any, error) {
 }
 
 func (e *Evaluator) evalSubOperator(left, right any) (any, error) {
+	left, right = widen(left, right)
+
 	switch l := left.(type) {
 	case int64:
 		switch r := right.(type) {
 		case int64:
-			return l - r, nil
+			return e.subInt(l, r)
 		case float64:
 			return float64(l) - r, nil
 		default:
@@ -670,17 +972,98 @@ func (e *Evaluator) evalSubOperator(left, right any) (any, error) {
 			return nil, fmt.Errorf("subtraction not supported for %s and %s",
 				builtin.TypeStr(l), builtin.TypeStr(r))
 		}
+	case *big.Int:
+		switch r := right.(type) {
+		case *big.Int:
+			return objects.NormalizeBig(new(big.Int).Sub(l, r)), nil
+		default:
+			return nil, fmt.Errorf("subtraction not supported for %s and %s",
+				builtin.TypeStr(l), builtin.TypeStr(r))
+		}
+	case *objects.SetObject:
+		return e.evalSetOperator("difference", l, right)
 	default:
 		return nil, fmt.Errorf("subtraction not supported for %s", builtin.TypeStr(l))
 	}
 }
 
+// applies `|`, `&` or `-` to two sets, which all return a new set
+func (e *Evaluator) evalSetOperator(operation string, left, right any) (any, error) {
+	l, ok1 := left.(*objects.SetObject)
+	r, ok2 := right.(*objects.SetObject)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("%s not supported for %s and %s",
+			operation, builtin.TypeStr(left), builtin.TypeStr(right))
+	}
+
+	var result *objects.SetObject
+	switch operation {
+	case "union":
+		result = l.Union(r)
+	case "intersection":
+		result = l.Intersection(r)
+	default:
+		result = l.Difference(r)
+	}
+	if err := e.limits.allocate(result.Len()); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// reports whether an element is in a set, array or deque, a key
+// is in a map or a string contains another string
+func (e *Evaluator) evalInOperator(left, right any) (any, error) {
+	var list []any
+	switch r := right.(type) {
+	case *objects.SetObject:
+		if err := builtin.CheckKey(left); err != nil {
+			return nil, err
+		}
+		return r.Has(left), nil
+	case *objects.MapObject:
+		if err := builtin.CheckKey(left); err != nil {
+			return nil, err
+		}
+		_, ok := r.Get(left)
+		return ok, nil
+	case string:
+		l, ok := left.(string)
+		if !ok {
+			return nil, fmt.Errorf("membership in string expects a string, got=%s",
+				builtin.TypeStr(left))
+		}
+		return gostrings.Contains(r, l), nil
+	case *objects.ArrayObject:
+		list = r.List
+	case *objects.DequeObject:
+		list = r.List()
+	default:
+		return nil, fmt.Errorf("membership not supported for %s", builtin.TypeStr(right))
+	}
+
+	for _, elem := range list {
+		equal, err := e.evalEqOperator(left, elem)
+		if err != nil {
+			return nil, err
+		}
+		if equal.(bool) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (e *Evaluator) evalMulOperator(left, right any) (any, error) {
+	left, right = widen(left, right)
+
 	switch l := left.(type) {
 	case int64:
 		switch r := right.(type) {
 		case int64:
-			return l * r, nil
+			return e.mulInt(l, r)
 		case float64:
 			return float64(l) * r, nil
 		default:
@@ -697,17 +1080,27 @@ func (e *Evaluator) evalMulOperator(left, right any) (any, error) {
 			return nil, fmt.Errorf("multiplication not supported for %s and %s",
 				builtin.TypeStr(l), builtin.TypeStr(r))
 		}
+	case *big.Int:
+		switch r := right.(type) {
+		case *big.Int:
+			return objects.NormalizeBig(new(big.Int).Mul(l, r)), nil
+		default:
+			return nil, fmt.Errorf("multiplication not supported for %s and %s",
+				builtin.TypeStr(l), builtin.TypeStr(r))
+		}
 	default:
 		return nil, fmt.Errorf("multiplication not supported for %s", builtin.TypeStr(l))
 	}
 }
 
 func (e *Evaluator) evalDivOperator(left, right any) (any, error) {
+	left, right = widen(left, right)
+
 	switch l := left.(type) {
 	case int64:
 		switch r := right.(type) {
 		case int64:
-			return l / r, nil
+			return e.divInt(l, r)
 		case float64:
 			return float64(l) / r, nil
 		default:
@@ -724,12 +1117,22 @@ func (e *Evaluator) evalDivOperator(left, right any) (any, error) {
 			return nil, fmt.Errorf("division not supported for %s and %s",
 				builtin.TypeStr(l), builtin.TypeStr(r))
 		}
+	case *big.Int:
+		switch r := right.(type) {
+		case *big.Int:
+			return bigDiv(l, r)
+		default:
+			return nil, fmt.Errorf("division not supported for %s and %s",
+				builtin.TypeStr(l), builtin.TypeStr(r))
+		}
 	default:
 		return nil, fmt.Errorf("division not supported for %s", builtin.TypeStr(l))
 	}
 }
 
 func (e *Evaluator) evalLtOperator(left, right any) (any, error) {
+	left, right = widen(left, right)
+
 	switch l := left.(type) {
 	case int64:
 		switch r := right.(type) {
@@ -759,6 +1162,26 @@ func (e *Evaluator) evalLtOperator(left, right any) (any, error) {
 			return nil, fmt.Errorf("cannot compare types %s and %s",
 				builtin.TypeStr(l), builtin.TypeStr(r))
 		}
+	case *big.Int:
+		switch r := right.(type) {
+		case *big.Int:
+			return l.Cmp(r) < 0, nil
+		default:
+			return nil, fmt.Errorf("cannot compare types %s and %s",
+				builtin.TypeStr(l), builtin.TypeStr(r))
+		}
+	case *objects.ArrayObject:
+		switch r := right.(type) {
+		case *objects.ArrayObject:
+			order, err := e.compareArrays(l, r, nil)
+			if err != nil {
+				return nil, err
+			}
+			return order < 0, nil
+		default:
+			return nil, fmt.Errorf("cannot compare types %s and %s",
+				builtin.TypeStr(l), builtin.TypeStr(r))
+		}
 	default:
 		return nil, fmt.Errorf("comparison not supported for %s", builtin.TypeStr(l))
 	}
@@ -777,6 +1200,15 @@ func (e *Evaluator) evalGtOperator(left, right any) (any, error) {
 }
 
 func (e *Evaluator) evalEqOperator(left, right any) (any, error) {
+	return e.equal(left, right, nil)
+}
+
+// equal compares arrays, maps and deques by their content. visiting
+// holds the pairs of containers being compared, a pair met again
+// while comparing its own elements is equal so that cycles end
+func (e *Evaluator) equal(left, right any, visiting map[pair]bool) (bool, error) {
+	left, right = widen(left, right)
+
 	switch l := left.(type) {
 	case int64:
 		switch r := right.(type) {
@@ -796,6 +1228,13 @@ func (e *Evaluator) evalEqOperator(left, right any) (any, error) {
 		default:
 			return false, nil
 		}
+	case *big.Int:
+		switch r := right.(type) {
+		case *big.Int:
+			return l.Cmp(r) == 0, nil
+		default:
+			return false, nil
+		}
 	case bool:
 		switch r := right.(type) {
 		case bool:
@@ -813,17 +1252,46 @@ func (e *Evaluator) evalEqOperator(left, right any) (any, error) {
 	case *objects.ArrayObject:
 		switch r := right.(type) {
 		case *objects.ArrayObject:
-			return slices.Equal(l.List, r.List), nil
+			return e.equalLists(l, r, l.List, r.List, visiting)
 		default:
 			return false, nil
 		}
 	case *objects.MapObject:
 		switch r := right.(type) {
 		case *objects.MapObject:
-			return maps.Equal(l.Map, r.Map), nil
+			return e.equalMaps(l, r, visiting)
 		default:
 			return false, nil
 		}
+	case *objects.SetObject:
+		switch r := right.(type) {
+		case *objects.SetObject:
+			return l.Len() == r.Len() && l.IsSubset(r), nil
+		default:
+			return false, nil
+		}
+	case *objects.DequeObject:
+		switch r := right.(type) {
+		case *objects.DequeObject:
+			return e.equalLists(l, r, l.List(), r.List(), visiting)
+		default:
+			return false, nil
+		}
+	case *objects.FileObject, *objects.HeapObject:
+		// handles and heaps are equal only to themselves
+		return l == right, nil
+	case *objects.RegexObject:
+		// regexes are equal when they have the same pattern
+		r, ok := right.(*objects.RegexObject)
+		return ok && l.Regexp.String() == r.Regexp.String(), nil
+	case objects.FuncObject:
+		// functions are equal when they are the same
+		// literal closed over the same environment
+		r, ok := right.(objects.FuncObject)
+		return ok && l == r, nil
+	case common.Sanitizer:
+		r, ok := right.(common.Sanitizer)
+		return ok && reflect.ValueOf(l).Pointer() == reflect.ValueOf(r).Pointer(), nil
 	case nil:
 		switch right.(type) {
 		case nil:
@@ -832,7 +1300,7 @@ func (e *Evaluator) evalEqOperator(left, right any) (any, error) {
 			return false, nil
 		}
 	default:
-		return nil, fmt.Errorf("equality not supported for %s", builtin.TypeStr(l))
+		return false, fmt.Errorf("equality not supported for %s", builtin.TypeStr(l))
 	}
 }
 
@@ -853,34 +1321,19 @@ func (e *Evaluator) evalPrefixExpression(expr *ast.PrefixExpression) (any, error
 }
 
 func (e *Evaluator) evalBangOperator(expr any) (any, error) {
-	return !isTruthy(expr), nil
+	return !common.IsTruthy(expr), nil
 }
 
 func (e *Evaluator) evalNegateOperator(expr any) (any, error) {
 	switch v := expr.(type) {
 	case int64:
-		return -v, nil
+		return e.negInt(v)
 	case float64:
 		return -v, nil
+	case *big.Int:
+		return objects.NormalizeBig(new(big.Int).Neg(v)), nil
 	default:
-		return nil, fmt.Errorf("cannot negate value of type %s", builtin.TypeStr(e))
-	}
-}
-
-func isTruthy(value any) bool {
-	switch value {
-	case false:
-		fallthrough
-	case nil:
-		fallthrough
-	case int64(0):
-		fallthrough
-	case 0.0:
-		fallthrough
-	case "":
-		return false
-	default:
-		return true
+		return nil, fmt.Errorf("cannot negate value of type %s", builtin.TypeStr(expr))
 	}
 }
 
@@ -1,15 +1,27 @@
 package evaluator
 
 import (
+	"RoLang/ast"
 	"RoLang/evaluator/objects"
 	"RoLang/lexer"
 	"RoLang/parser"
+	"RoLang/stdlib"
+	"RoLang/stdlib/common"
+	rostrings "RoLang/stdlib/strings"
 
+	"bytes"
+	"context"
 	"errors"
+	"io/fs"
 	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 type expectType struct {
@@ -202,13 +214,11 @@ func TestMapLiteral(t *testing.T) {
 	if !ok {
 		t.Fatalf("object is not Map. got=%T (%+v)", expr, expr)
 	}
-	result := obj.Map
-
-	if len(result) != 2 {
-		t.Fatalf("map has wrong num of elements. got=%d", len(result))
+	if obj.Len() != 2 {
+		t.Fatalf("map has wrong num of elements. got=%d", obj.Len())
 	}
 
-	val1, ok := result["hello"]
+	val1, ok := obj.Get("hello")
 	if !ok {
 		t.Fatal(`map has no key "hello"`)
 	}
@@ -217,7 +227,7 @@ func TestMapLiteral(t *testing.T) {
 		return
 	}
 
-	val2, ok := result["world"]
+	val2, ok := obj.Get("world")
 	if !ok {
 		t.Fatal(`map has no key "world"`)
 	}
@@ -302,8 +312,1300 @@ func TestErrorStatements(t *testing.T) {
 	}
 }
 
+func TestRuntimeTypeChecks(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{`let x: int = "a";`, "cannot use string as type int in let x"},
+		{`let x: [int] = [1, "a"];`, "cannot use array as type [int] in let x"},
+		{`fn f(x: string) { } f(1);`, "cannot use int as string in argument 1"},
+		{`let s: set = deques.new();`, "cannot use deque as type set in let s"},
+		{`fn f(r: regex) { } f("a+");`, "cannot use string as regex in argument 1"},
+	}
+
+	for i, test := range tests {
+		l := lexer.New("evaluator_test", test.input)
+		p := parser.New(l)
+		e := New(WithTypeChecks())
+
+		program, errs := p.Parse()
+		checkErrors(t, errs)
+
+		_, errs = e.Evaluate(program)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+
+	// stdlib values can be annotated with their type names
+	l := lexer.New("evaluator_test", `let s: set = sets.new(); let d: deque = deques.new(); let h: heap = heaps.new(); let r: regex | null = regex.compile("a+");`)
+	p := parser.New(l)
+	e := New(WithTypeChecks())
+	program, errs := p.Parse()
+	checkErrors(t, errs)
+	_, errs = e.Evaluate(program)
+	checkErrors(t, errs)
+
+	// annotations are ignored unless type checks are enabled
+	errs = testEvalStatements(t, `let x: int = "a"; let y: float = 1;`)
+	checkErrors(t, errs)
+}
+
+func TestBigIntegers(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"4611686018427387904 * 4", "18446744073709551616"},
+		{"-(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"(9223372036854775807 + 1) * 2 / 2", "9223372036854775808"},
+		{"(9223372036854775807 + 1) - 1", "9223372036854775807"},
+		{`"n=" + (9223372036854775807 + 1)`, "n=9223372036854775808"},
+	}
+
+	for i, test := range tests {
+		eval := testEvalExpression(t, test.input)
+		if str := rostrings.From(eval); str != test.expect {
+			t.Errorf("test[%d] wrong value. got=%s, expect=%s", i, str, test.expect)
+		}
+	}
+
+	// demoted results are plain integers again
+	eval := testEvalExpression(t, "(9223372036854775807 + 1) - 1")
+	if !testIntegerObject(t, eval, 9223372036854775807) {
+		return
+	}
+
+	comparisons := []struct {
+		input  string
+		expect bool
+	}{
+		{"9223372036854775807 + 1 > 9223372036854775807", true},
+		{"9223372036854775807 + 1 == 9223372036854775807 + 1", true},
+		{"9223372036854775807 + 1 < 1.5", false},
+		{`{9223372036854775807 + 1: "big"}[9223372036854775807 + 1] == "big"`, true},
+	}
+
+	for i, test := range comparisons {
+		eval := testEvalExpression(t, test.input)
+		if !testBooleanObject(t, eval, test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestCheckedOverflow(t *testing.T) {
+	l := lexer.New("evaluator_test", "let x = 9223372036854775807 + 1;")
+	p := parser.New(l)
+	e := New(WithCheckedOverflow())
+
+	program, errs := p.Parse()
+	checkErrors(t, errs)
+
+	_, errs = e.Evaluate(program)
+	testErrors(t, errors.Join(errs...).Error(), "integer overflow in 9223372036854775807 + 1")
+}
+
+func TestUnicodeStrings(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect any
+	}{
+		{`"héllo"[1]`, "é"},
+		{`"日本語"[2]`, "語"},
+		{`strings.len("日本語")`, 3},
+		{`strings.slice("naïve café", 2, 5)`, "ïve"},
+		{`unicode.isLetter("é")`, true},
+		{`unicode.isDigit("١٢٣")`, true},
+		{`unicode.isUpper("Éa")`, false},
+		{`unicode.category("A")`, "Lu"},
+		{`unicode.category("٣")`, "Nd"},
+		{"strings.len(unicode.normalize(\"e\u0301\", \"NFC\"))", 1},
+		{"unicode.normalize(\"é\", \"NFD\") == \"e\u0301\"", true},
+	}
+
+	for i, test := range tests {
+		eval := testEvalExpression(t, test.input)
+		if !testPrimaryObject(t, eval, test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestTopLevelReturn(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect *Exit
+	}{
+		{"let a = 1;", nil},
+		{"return;", &Exit{}},
+		{"return 2;", &Exit{Code: 2, Value: int64(2)}},
+		{"let i = 0; loop i < 10 { if i == 3 { return i; } i = i + 1; }", &Exit{Code: 3, Value: int64(3)}},
+	}
+
+	for i, test := range tests {
+		l := lexer.New("evaluator_test", test.input)
+		p := parser.New(l)
+		e := New()
+
+		program, errs := p.Parse()
+		checkErrors(t, errs)
+
+		exit, errs := e.Evaluate(program)
+		checkErrors(t, errs)
+
+		if (exit == nil) != (test.expect == nil) || exit != nil && *exit != *test.expect {
+			t.Errorf("test[%d]: expected exit %v, got %v", i, test.expect, exit)
+		}
+	}
+
+	// the evaluator keeps working after a script asked to exit
+	l := lexer.New("evaluator_test", "let a = 1; return 1; a = 2;")
+	p := parser.New(l)
+	e := New()
+
+	program, errs := p.Parse()
+	checkErrors(t, errs)
+	e.Evaluate(program)
+
+	testIdentifier(t, e, "a", int64(1))
+	if e.env != e.globals {
+		t.Errorf("expected the global environment to be restored")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	expired, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	tests := []struct {
+		input  string
+		option Option
+		limit  error
+		expect string
+	}{
+		{"loop { }", WithMaxSteps(1000), ErrStepLimit, "step limit exceeded (1000 steps)"},
+		{"loop { }", WithContext(expired), context.DeadlineExceeded, "execution stopped: context deadline exceeded"},
+		{"fn f(n) { return f(n + 1); } f(0);", WithMaxCallDepth(100), ErrCallDepth, "maximum call depth exceeded (100 calls)"},
+		{"fn f(n) { return f(n + 1); } f(0);", func(*Evaluator) {}, ErrCallDepth, "maximum call depth exceeded (10000 calls)"},
+		{"let a = []; loop { arrays.push(a, 1); }", WithMaxElements(100), ErrElementLimit, "element limit exceeded (100 elements)"},
+		{"let m = {}; let i = 0; loop { m[i] = i; i = i + 1; }", WithMaxElements(100), ErrElementLimit, "element limit exceeded (100 elements)"},
+		{"let a = [1, 2, 3]; loop { a = a + a; }", WithMaxElements(100), ErrElementLimit, "element limit exceeded (100 elements)"},
+	}
+
+	for i, test := range tests {
+		l := lexer.New("evaluator_test", test.input)
+		p := parser.New(l)
+		e := New(test.option)
+
+		program, errs := p.Parse()
+		checkErrors(t, errs)
+
+		_, errs = e.Evaluate(program)
+		err := errors.Join(errs...)
+		if !errors.Is(err, test.limit) {
+			t.Errorf("test[%d]: expected %v, got %v", i, test.limit, err)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "\nevaluator_test:1:") {
+			t.Errorf("test[%d]: expected a location, got %q", i, err)
+		}
+		// deep traces are shortened, so only the message is compared
+		if !strings.HasSuffix(err.Error(), " "+test.expect) {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, err)
+		}
+	}
+
+	// limits are counted per evaluation
+	l := lexer.New("evaluator_test", "let a = [1, 2, 3];")
+	p := parser.New(l)
+	e := New(WithMaxElements(5))
+
+	program, errs := p.Parse()
+	checkErrors(t, errs)
+
+	_, errs = e.Evaluate(program)
+	checkErrors(t, errs)
+	_, errs = e.Evaluate(&ast.Program{Statements: []ast.Statement{
+		&ast.ExpressionStatement{Expression: program.Statements[0].(*ast.LetStatement).InitValue},
+	}})
+	checkErrors(t, errs)
+}
+
+func TestPolicy(t *testing.T) {
+	tests := []struct {
+		input  string
+		policy stdlib.Policy
+		expect string
+	}{
+		{"io.readln();", stdlib.Policy{Deny: []string{"io.readln"}}, "permission denied: io.readln"},
+		{"io.println(1);", stdlib.Policy{Deny: []string{"io"}}, "permission denied: io.println"},
+		{"io.println(1);", stdlib.Policy{Allow: []string{"strings"}}, "permission denied: io.println"},
+		{"type(1);", stdlib.Policy{Allow: []string{"io"}}, "permission denied: builtin.type"},
+		{"io.readln();", stdlib.Policy{Allow: []string{"io.println"}, Hide: true}, `no method "readln" found in io module`},
+		{"io.println(1);", stdlib.Policy{Deny: []string{"io"}, Hide: true}, `no module named "io"`},
+		{"type(1);", stdlib.Policy{Deny: []string{"builtin.type"}, Hide: true}, "variable not found: type"},
+		{"math.pi;", stdlib.Policy{Deny: []string{"math.pi"}}, "permission denied: math.pi"},
+		{"math.pi;", stdlib.Policy{Allow: []string{"math.sqrt"}, Hide: true}, `no method "pi" found in math module`},
+	}
+
+	for i, test := range tests {
+		l := lexer.New("evaluator_test", test.input)
+		p := parser.New(l)
+		e := New(WithPolicy(test.policy))
+
+		program, errs := p.Parse()
+		checkErrors(t, errs)
+
+		_, errs = e.Evaluate(program)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+
+	// permitted functions keep working
+	l := lexer.New("evaluator_test", `let s = strings.len("abc"); let t = type(s);`)
+	p := parser.New(l)
+	e := New(WithPolicy(stdlib.Policy{Allow: []string{"strings.len", "builtin"}, Hide: true}))
+
+	program, errs := p.Parse()
+	checkErrors(t, errs)
+
+	_, errs = e.Evaluate(program)
+	checkErrors(t, errs)
+	testIdentifier(t, e, "t", "int")
+}
+
+// a module whose functions are built on top of the evaluator's caller
+type callbackModule map[string]common.Sanitizer
+
+func (m callbackModule) Dispatcher(name string) (common.Sanitizer, error) {
+	return m[name], nil
+}
+
+func TestCallbacks(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"io.print(test.apply(fn(x) { return x * 2; }, 21));", "42"},
+		{"let n = 10; io.print(test.apply(fn(x) { n = n + x; return n; }, 5), n);", "1515"},
+		{"io.print(test.apply(strings.len, \"abc\"));", "3"},
+		{"io.print(test.apply(test.apply, fn(x) { return x; }, 1));", "1"},
+		{"loop { test.apply(fn() { io.print(1); }); break; } io.print(2);", "12"},
+		{"fn f() { loop { return 3; } } io.print(test.apply(f));", "3"},
+	}
+
+	for i, test := range tests {
+		stdout, errs := testEvalCallbacks(t, test.input)
+		checkErrors(t, errs)
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{"test.apply(fn(x) { return x + true; }, 1);", "\nevaluator_test:1:1: \nevaluator_test:1:11: \nevaluator_test:1:20: \nevaluator_test:1:29: addition not supported for int and bool"},
+		{"loop { test.apply(fn() { break; }); }", "\nevaluator_test:1:1: \nevaluator_test:1:6: \nevaluator_test:1:8: \nevaluator_test:1:18: break outside of a loop"},
+		{"test.apply(1);", "\nevaluator_test:1:1: \nevaluator_test:1:11: not a callable int"},
+	}
+
+	for i, test := range errTests {
+		_, errs := testEvalCallbacks(t, test.input)
+		if err := errors.Join(errs...); err == nil || err.Error() != test.expect {
+			t.Errorf("test[%d]: expected error %q, got %q", i, test.expect, err)
+		}
+	}
+}
+
+func testEvalCallbacks(t *testing.T, input string) (string, []error) {
+	l := lexer.New("evaluator_test", input)
+	p := parser.New(l)
+
+	var stdout bytes.Buffer
+	e := New(WithStdout(&stdout))
+	e.stdlib.Modules["test"] = callbackModule{
+		"apply": func(args ...any) (any, error) {
+			return e.stdlib.Caller.Call(args[0], args[1:]...)
+		},
+	}
+
+	program, errs := p.Parse()
+	checkErrors(t, errs)
+
+	_, errs = e.Evaluate(program)
+	return stdout.String(), errs
+}
+
+func TestArrayFunctions(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"arrays.map([1, 2, 3], fn(x) { return x * x; })", "[1, 4, 9]"},
+		{"arrays.map([5, 6], fn(x, i) { return i; })", "[0, 1]"},
+		{"arrays.map([\"a\", \"bc\"], strings.len)", "[1, 2]"},
+		{"arrays.filter([1, 2, 3, 4], fn(x) { return x > 2; })", "[3, 4]"},
+		{"arrays.reduce([1, 2, 3], fn(acc, x) { return acc + x; })", "6"},
+		{"arrays.reduce([1, 2, 3], fn(acc, x) { return acc + x; }, 10)", "16"},
+		{"arrays.reduce([], fn(acc, x) { return acc + x; }, 0)", "0"},
+		{"arrays.find([1, 2, 3], fn(x) { return x > 1; })", "2"},
+		{"arrays.find([1, 2, 3], fn(x) { return x > 5; })", "null"},
+		{"arrays.findIndex([1, 2, 3], fn(x) { return x == 3; })", "2"},
+		{"arrays.findIndex([1, 2, 3], fn(x) { return x == 4; })", "-1"},
+		{"arrays.any([1, 2, 3], fn(x) { return x == 2; })", "true"},
+		{"arrays.any([], fn(x) { return true; })", "false"},
+		{"arrays.all([1, 2, 3], fn(x) { return x > 0; })", "true"},
+		{"arrays.all([1, 2, 3], fn(x) { return x > 1; })", "false"},
+		{"arrays.flatMap([1, 2], fn(x) { return [x, x * 10]; })", "[1, 10, 2, 20]"},
+		{"arrays.flatMap([[1], 2], fn(x) { return x; })", "[1, 2]"},
+		{"arrays.zip([1, 2, 3], [\"a\", \"b\"])", "[[1, a], [2, b]]"},
+		{"arrays.enumerate([\"a\", \"b\"])", "[[0, a], [1, b]]"},
+		{"arrays.groupBy([1, 2, 3, 4], fn(x) { return x / 2 * 2 == x; })[true]", "[2, 4]"},
+		{"arrays.partition([1, 2, 3, 4], fn(x) { return x > 2; })", "[[3, 4], [1, 2]]"},
+		{"arrays.chunk([1, 2, 3, 4, 5], 2)", "[[1, 2], [3, 4], [5]]"},
+		{"arrays.uniq([1, 2, 1, 1.0, \"a\", \"a\", 3])", "[1, 2, a, 3]"},
+		{"arrays.uniq([[1], [1], [2]])", "[[1], [2]]"},
+		{"arrays.reverse([1, 2, 3])", "[3, 2, 1]"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, "io.print("+test.input+");", "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	// callbacks see the variables they close over
+	stdout, _ := testEvalOutput(t, `
+		let total = 0;
+		arrays.each([1, 2, 3], fn(x) { total = total + x; });
+		io.print(total);
+	`, "")
+	if stdout != "6" {
+		t.Errorf("expected %q, got %q", "6", stdout)
+	}
+
+	// push appends the value, not the array itself
+	stdout, _ = testEvalOutput(t, `let a = [1, 2]; arrays.push(a, 3); io.print(arrays.len(a), a[2] == 3);`, "")
+	if stdout != "3true" {
+		t.Errorf("expected %q, got %q", "3true", stdout)
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{"arrays.map(1, fn(x) { return x; });", "map expects first argument to be array, got=int"},
+		{"arrays.filter([1], 2);", "filter expects second argument to be function, got=int"},
+		{"arrays.reduce([], fn(a, b) { return a; });", "reduce of empty array with no initial value"},
+		{"arrays.chunk([1], 0);", "chunk expects a positive size, got=0"},
+		{"arrays.groupBy([1], fn(x) { return {}; });", "only int, float, string, bool and array is allowed as key. got=map"},
+		{"arrays.map([1], fn(x, y, z) { return x; });", "incorrect no of arguments. got=1, expect=3"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+
+	// errors raised in a callback keep their location
+	errs := testEvalStatements(t, "arrays.map([1],\nfn(x) { return x + true; });")
+	expect := "\nevaluator_test:1:1: \nevaluator_test:1:11: \nevaluator_test:2:9: \nevaluator_test:2:18: addition not supported for int and bool"
+	if err := errors.Join(errs...); err == nil || err.Error() != expect {
+		t.Errorf("expected %q, got %q", expect, err)
+	}
+}
+
+func TestArraySorting(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"arrays.sort([3, 1.5, 2, 9223372036854775807 + 1, -1])", "[-1, 1.5, 2, 3, 9223372036854775808]"},
+		{"arrays.sort([\"b\", \"c\", \"a\"])", "[a, b, c]"},
+		{"arrays.sort([])", "[]"},
+		{"arrays.sortBy([\"ccc\", \"a\", \"bb\", \"d\"], strings.len)", "[a, d, bb, ccc]"},
+		{"arrays.sortBy([[2, \"x\"], [1, \"y\"], [2, \"a\"]], fn(p) { return p[0]; })", "[[1, y], [2, x], [2, a]]"},
+		{"arrays.sortWith([1, 3, 2], fn(a, b) { return b - a; })", "[3, 2, 1]"},
+		{"arrays.binarySearch([1, 3, 5, 7], 5)", "2"},
+		{"arrays.binarySearch([1, 3, 5, 7], 4)", "-1"},
+		{"arrays.indexOf([1, \"a\", 2.0], 2)", "2"},
+		{"arrays.indexOf([1, 2], 3)", "-1"},
+		{"arrays.contains([\"a\", \"b\"], \"b\")", "true"},
+		{"arrays.contains([1], \"1\")", "false"},
+		{"arrays.min([3, 1, 2])", "1"},
+		{"arrays.max([3, 1.5, 2])", "3"},
+		{"arrays.min([])", "null"},
+		{"arrays.sum([1, 2, 3.5])", "6.5"},
+		{"arrays.sum([9223372036854775807, 1])", "9223372036854775808"},
+		{"arrays.sum([])", "0"},
+		{"arrays.union([1, 2], [2, 3], [3, 4, 1])", "[1, 2, 3, 4]"},
+		{"arrays.intersection([1, 2, 3, 2], [2, 3, 4], [3, 2])", "[2, 3]"},
+		{"arrays.difference([1, 2, 3, 1], [2])", "[1, 3]"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, "io.print("+test.input+");", "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{"arrays.sort([1, \"a\"]);", "cannot compare types string and int"},
+		{"arrays.sort([true, false]);", "comparison not supported for bool"},
+		{"arrays.max([\"a\", 1]);", "cannot compare types string and int"},
+		{"arrays.sortWith([1, 2], fn(a, b) { return true; });", "sortWith expects comparator to return a number, got=bool"},
+		{"arrays.sum([1, \"a\"]);", "sum expects numbers, got=string"},
+		{"arrays.union([1]);", "union expects at least two arguments, got=1"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestStringFunctions(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"strings.trim(\"  héllo \")", "héllo"},
+		{"strings.trim(\"xxhixyx\", \"xy\")", "hi"},
+		{"strings.trimLeft(\"ééaé\", \"é\")", "aé"},
+		{"strings.trimRight(\" a \")", " a"},
+		{"strings.contains(\"héllo\", \"él\")", "true"},
+		{"strings.startsWith(\"héllo\", \"hé\")", "true"},
+		{"strings.endsWith(\"héllo\", \"x\")", "false"},
+		{"strings.indexOf(\"héllo\", \"l\")", "2"},
+		{"strings.lastIndexOf(\"héllo\", \"l\")", "3"},
+		{"strings.indexOf(\"héllo\", \"x\")", "-1"},
+		{"strings.replace(\"a-b-c\", \"-\", \"+\")", "a+b-c"},
+		{"strings.replaceAll(\"a-b-c\", \"-\", \"+\")", "a+b+c"},
+		{"strings.upper(\"straße\")", "STRASSE"},
+		{"strings.lower(\"ÀB\")", "àb"},
+		{"strings.title(\"hello wORLD\")", "Hello World"},
+		{"strings.repeat(\"ab\", 3)", "ababab"},
+		{"strings.repeat(\"ab\", 0)", ""},
+		{"strings.join([1, \"a\", true], \", \")", "1, a, true"},
+		{"strings.padLeft(\"7\", 3, \"0\")", "007"},
+		{"strings.padRight(\"é\", 4, \"ab\")", "éaba"},
+		{"strings.padLeft(\"abc\", 2)", "abc"},
+		{"strings.reverse(\"héllo\")", "olléh"},
+		{"strings.chars(\"hé\")", "[h, é]"},
+		{"strings.lines(\"a\r\nb\n\nc\n\")", "[a, b, , c]"},
+		{"strings.lines(\"\")", "[]"},
+		{"strings.count(\"aaaa\", \"aa\")", "2"},
+		{"strings.from(fn() {})", "function"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, "io.print("+test.input+");", "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{"strings.trim(1);", "trim expects first argument to be string, got=int"},
+		{"strings.trim(\"a\", \"b\", \"c\");", "trim expects one or two arguments, got=3"},
+		{"strings.contains(\"a\", 1);", "contains expects second argument to be string, got=int"},
+		{"strings.repeat(\"a\", -1);", "repeat expects a non-negative count, got=-1"},
+		{"strings.padLeft(\"a\", 3, \"\");", "padLeft expects a non-empty padding"},
+		{"strings.join(\"a\", \"\");", "join expects first argument to be array, got=string"},
+		{"strings.count(\"a\", \"\");", "count expects a non-empty substring"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"strings.format(\"{} is {:.2f}\", \"pi\", 3.14159)", "pi is 3.14"},
+		{"strings.format(\"{1}-{0}-{1}\", \"a\", \"b\")", "b-a-b"},
+		{"strings.format(\"{{}} {}\", [1, 2])", "{} [1, 2]"},
+		{"strings.format(\"[{:>5}|{:<4}|{:^7}]\", 42, \"é\", \"mid\")", "[   42|é   |  mid  ]"},
+		{"strings.format(\"{:*^6}\", \"ab\")", "**ab**"},
+		{"strings.format(\"{:05d} {:+d} {:05}\", -42, 7, 1.5)", "-0042 +7 001.5"},
+		{"strings.format(\"{:b} {:#o} {:x} {:#X}\", 5, 8, 255, 255)", "101 0o10 ff 0XFF"},
+		{"strings.format(\"{:x}\", 9223372036854775807 + 1)", "8000000000000000"},
+		{"strings.format(\"{:.1%} {:.2e} {:g}\", 0.256, 1234.5, 0.5)", "25.6% 1.23e+03 0.5"},
+		{"strings.format(\"{:.2f}\", 3)", "3.00"},
+		{"strings.format(\"{:.3}\", \"abcdef\")", "abc"},
+		{"strings.format(\"{}\", null)", "null"},
+		{"int(\"42\") + 1", "43"},
+		{"int(\" -7 \")", "-7"},
+		{"int(\"ff\", 16)", "255"},
+		{"int(\"0b101\", 0)", "5"},
+		{"int(\"99999999999999999999\")", "99999999999999999999"},
+		{"int(-3.9)", "-3"},
+		{"int(1e20)", "100000000000000000000"},
+		{"int(true)", "1"},
+		{"float(\"2.5\") * 2", "5"},
+		{"float(3)", "3"},
+		{"bool(\"true\")", "true"},
+		{"bool(0)", "false"},
+		{"bool([])", "true"},
+		{"str(12) + str(1.5)", "121.5"},
+		{"str(255, 16)", "ff"},
+		{"str(-5, 2)", "-101"},
+		{"str([1, \"a\"])", "[1, a]"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, "io.print("+test.input+");", "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	stdout, _ := testEvalOutput(t, "io.printf(\"{}: {:.1f}\n\", \"x\", 2);", "")
+	if stdout != "x: 2.0\n" {
+		t.Errorf("expected printf output %q, got %q", "x: 2.0\n", stdout)
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{"strings.format(\"{} {}\", 1);", "format expects at least 2 values, got=1"},
+		{"strings.format(\"{\", 1);", "unclosed placeholder in format string at 0"},
+		{"strings.format(\"a}\");", "single '}' in format string at 1"},
+		{"strings.format(\"{} {0}\", 1);", "cannot mix automatic and numbered placeholders in format string"},
+		{"strings.format(\"{:q}\", 1);", "invalid format spec \"q\""},
+		{"strings.format(\"{:d}\", 1.5);", "format verb 'd' expects int, got=float"},
+		{"strings.format(\"{:+}\", \"a\");", "sign and '#' are not allowed with string values"},
+		{"int(\"12a\");", "invalid int literal \"12a\" in base 10"},
+		{"int(\"12\", 1);", "`int` expects base to be 0 or between 2 and 36, got=1"},
+		{"int(1.5, 2);", "`int` accepts a base only for strings, got=float"},
+		{"int([]);", "cannot convert array to int"},
+		{"float(\"abc\");", "invalid float literal \"abc\""},
+		{"bool(\"yes\");", "invalid bool literal \"yes\""},
+		{"str(1.5, 2);", "`str` accepts a base only for ints, got=float"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestMathModule(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"math.pi", "3.141592653589793"},
+		{"math.maxInt + 1", "9223372036854775808"},
+		{"math.inf > 1e308", "true"},
+		{"math.abs(-3)", "3"},
+		{"math.abs(-2.5)", "2.5"},
+		{"math.abs(math.minInt)", "9223372036854775808"},
+		{"math.floor(2.7)", "2"},
+		{"type(math.floor(2.7))", "float"},
+		{"type(math.floor(2))", "int"},
+		{"math.ceil(-2.5)", "-2"},
+		{"math.round(2.5)", "3"},
+		{"math.round(3.14159, 2)", "3.14"},
+		{"math.round(1.5, 400)", "1.5"},
+		{"math.round(1e300, 20) == 1e300", "true"},
+		{"math.round(1234.5, -2)", "1200"},
+		{"math.round(1.5e300, -400)", "0"},
+		{"math.round(-2.5, -400)", "-0"},
+		{"math.sqrt(16)", "4"},
+		{"math.pow(2, 10)", "1024"},
+		{"math.pow(2, 64)", "18446744073709551616"},
+		{"math.pow(2, -1)", "0.5"},
+		{"math.pow(2.0, 3)", "8"},
+		{"type(math.pow(2.0, 3))", "float"},
+		{"math.exp(0)", "1"},
+		{"math.log(math.e)", "1"},
+		{"math.log(8, 2)", "3"},
+		{"math.log2(1024)", "10"},
+		{"math.log10(1000)", "3"},
+		{"math.sin(0)", "0"},
+		{"math.cos(0)", "1"},
+		{"math.atan2(1, 1) * 4 == math.pi", "true"},
+		{"math.min(3, 1.5, 2)", "1.5"},
+		{"math.max(3, 9223372036854775807 + 1, 2.5)", "9223372036854775808"},
+		{"math.min(2, 2.0)", "2"},
+		{"math.clamp(15, 0, 10)", "10"},
+		{"math.clamp(-1, 0, 10)", "0"},
+		{"math.clamp(5.5, 0, 10)", "5.5"},
+		{"math.gcd(12, -18, 30)", "6"},
+		{"math.gcd(0, 0)", "0"},
+		{"math.lcm(4, 6)", "12"},
+		{"math.lcm(9223372036854775807, 2)", "18446744073709551614"},
+		{"math.isNaN(math.nan)", "true"},
+		{"math.isNaN(1)", "false"},
+		{"math.isInf(-math.inf)", "true"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, "io.print("+test.input+");", "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{"math.sqrt(\"4\");", "sqrt expects argument to be number, got=string"},
+		{"math.pow(2, \"a\");", "pow expects second argument to be number, got=string"},
+		{"math.pow(3, 10000000);", "pow result is too large"},
+		{"math.min();", "min expects at least one argument"},
+		{"math.max(1, true);", "max expects all arguments to be number, arg=2 got=bool"},
+		{"math.clamp(1, 10, 0);", "clamp expects lower bound to not be greater than upper bound"},
+		{"math.gcd(1.5, 2);", "gcd expects all arguments to be int, arg=1 got=float"},
+		{"math.tau;", "no method \"tau\" found in math module"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestRandomModule(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"io.print(arrays.contains([3, 4, 5], random.int(3, 5)));", "true"},
+		{"io.print(random.int(7, 7));", "7"},
+		{"io.print(type(random.int(math.minInt, math.maxInt)));", "int"},
+		{"let f = random.float(); io.print(f >= 0, f < 1);", "truetrue"},
+		{"let f = random.float(2, 3); io.print(f >= 2, f < 3);", "truetrue"},
+		{"io.print(arrays.contains([1, 2, 3], random.choice([1, 2, 3])));", "true"},
+		{"io.print(arrays.sort(random.shuffle([3, 1, 2])));", "[1, 2, 3]"},
+		{"io.print(arrays.sort(random.sample([1, 2, 3, 4], 4)));", "[1, 2, 3, 4]"},
+		{"io.print(random.sample([1, 2], 0));", "[]"},
+		{"io.print(arrays.contains([1, 2, 3], random.secureInt(1, 3)));", "true"},
+		{"io.print(strings.len(random.secureToken(16)));", "32"},
+		{"io.print(random.secureToken(8) == random.secureToken(8));", "false"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, test.input, "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{"random.int(5, 1);", "int expects lower bound to not be greater than upper bound"},
+		{"random.int(1.5, 2);", "int expects first argument to be int, got=float"},
+		{"random.choice([]);", "choice from an empty array"},
+		{"random.sample([1, 2], 3);", "sample size 3 is out of range for array of length 2"},
+		{"random.seed(\"a\");", "seed expects argument to be int, got=string"},
+		{"random.secureToken(0);", "secureToken expects a size between 1 and 65536, got=0"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestFsModule(t *testing.T) {
+	root := t.TempDir()
+	policy := stdlib.Policy{FS: common.FSAccess{Root: root}}
+
+	run := func(input string) (string, error) {
+		l := lexer.New("evaluator_test", input)
+		p := parser.New(l)
+
+		var stdout bytes.Buffer
+		e := New(WithPolicy(policy), WithStdout(&stdout))
+
+		program, errs := p.Parse()
+		checkErrors(t, errs)
+
+		_, errs = e.Evaluate(program)
+		return stdout.String(), errors.Join(errs...)
+	}
+
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"fs.writeFile(\"a.txt\", \"one\n\"); fs.appendFile(\"a.txt\", \"two\n\"); io.print(fs.readFile(\"a.txt\"));", "one\ntwo\n"},
+		{`io.print(fs.exists("a.txt"), fs.exists("b.txt"));`, "truefalse"},
+		{`let s = fs.stat("a.txt"); io.print(s["name"], s["size"], s["isDir"]);`, "a.txt8false"},
+		{`fs.mkdirAll("d/e"); fs.writeFile("d/x", ""); io.print(fs.listDir("d"));`, "[e, x]"},
+		{`fs.rename("d/x", "d/y"); fs.remove("d/e"); io.print(fs.listDir("d"));`, "[y]"},
+		{`let f = fs.open("a.txt"); io.print(fs.readLine(f), fs.readLine(f), fs.readLine(f)); fs.close(f);`, "onetwonull"},
+		{"let f = fs.open(\"b.txt\", \"w\"); fs.write(f, \"x=\", 1, \"\n\"); fs.close(f); io.print(fs.readFile(\"b.txt\"));", "x=1\n"},
+		{`let f = fs.open("b.txt", "a"); fs.write(f, [2]); fs.close(f); io.print(fs.readFile("b.txt"), type(f));`, "x=1\n[2]file"},
+	}
+
+	for i, test := range tests {
+		stdout, err := run(test.input)
+		if err != nil {
+			t.Fatalf("test[%d]: unexpected error: %v", i, err)
+		}
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{`fs.readFile("missing.txt");`, `readFile "missing.txt": no such file or directory`},
+		{`fs.readFile("../outside.txt");`, `permission denied: readFile "../outside.txt": path is outside of ` + strconv.Quote(root)},
+		{`fs.remove("d");`, `remove "d": directory not empty`},
+		{`fs.listDir("a.txt");`, `listDir "a.txt": not a directory`},
+		{`let f = fs.open("a.txt"); fs.close(f); fs.readLine(f);`, `readLine "a.txt": file already closed`},
+		{`let f = fs.open("a.txt"); fs.write(f, "x");`, `write "a.txt": file is not open for writing`},
+		{`fs.open("a.txt", "rw");`, `open expects mode to be "r", "w" or "a", got="rw"`},
+		{`fs.writeFile(1, "x");`, "writeFile expects first argument to be string, got=int"},
+		{`fs.writeFile("link", "x");`, `permission denied: writeFile "link": path is outside of ` + strconv.Quote(root)},
+		{`fs.writeFile("dirLink/x", "x");`, `permission denied: writeFile "dirLink/x": path is outside of ` + strconv.Quote(root)},
+		{`fs.mkdirAll("dirLink/y");`, `permission denied: mkdirAll "dirLink/y": path is outside of ` + strconv.Quote(root)},
+	}
+
+	// links whose targets do not exist yet cannot lead out of the root
+	outside := filepath.Join(filepath.Dir(root), "outside")
+	if err := os.Mkdir(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../outside/pwned", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "dir"), filepath.Join(root, "dirLink")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("created.txt", filepath.Join(root, "inside")); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, test := range errTests {
+		_, err := run(test.input)
+		if err == nil {
+			t.Fatalf("test[%d]: expected an error", i)
+		}
+		if !testErrors(t, err.Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Errorf("expected nothing to be created outside of the root, got %v", entries)
+	}
+	if stdout, err := run(`fs.writeFile("inside", "x"); io.print(fs.readFile("created.txt"));`); err != nil || stdout != "x" {
+		t.Errorf("expected a link within the root to be followed, got %q, %v", stdout, err)
+	}
+
+	// os errors stay matchable
+	if _, err := run(`fs.readFile("missing.txt");`); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+
+	policy.FS.ReadOnly = true
+	_, err := run(`fs.writeFile("a.txt", "");`)
+	if err == nil || !testErrors(t, err.Error(), `permission denied: writeFile "a.txt": file system is read-only`) {
+		t.Logf("read-only test\n")
+	}
+}
+
+func TestOsModule(t *testing.T) {
+	root := t.TempDir()
+	run := func(input string, opts ...Option) (*Exit, string, error) {
+		l := lexer.New("evaluator_test", input)
+		p := parser.New(l)
+
+		var stdout bytes.Buffer
+		e := New(append(opts, WithStdout(&stdout))...)
+
+		program, errs := p.Parse()
+		checkErrors(t, errs)
+
+		exit, errs := e.Evaluate(program)
+		return exit, stdout.String(), errors.Join(errs...)
+	}
+
+	_, stdout, err := run(`io.print(os.args, strings.len(os.cwd()) > 0);`, WithArgs("a", "b"))
+	if err != nil || stdout != "[a, b]true" {
+		t.Errorf("expected %q, got %q (%v)", "[a, b]true", stdout, err)
+	}
+
+	// setenv only changes the script's own environment
+	_, stdout, err = run(`os.setenv("ROLANG_TEST_VAR", "1"); io.print(os.getenv("ROLANG_TEST_VAR")); os.setenv("ROLANG_TEST_VAR", null); io.print(os.getenv("ROLANG_TEST_VAR"));`)
+	if err != nil || stdout != "1null" {
+		t.Errorf("expected %q, got %q (%v)", "1null", stdout, err)
+	}
+	if _, ok := os.LookupEnv("ROLANG_TEST_VAR"); ok {
+		t.Errorf("expected the process environment to be left alone")
+	}
+
+	// exit unwinds from inside functions and callbacks
+	exit, stdout, err := run(`fn f() { arrays.each([1], fn(x) { io.print("a"); os.exit(3); }); } f(); io.print("b");`)
+	if err != nil || exit == nil || exit.Code != 3 || stdout != "a" {
+		t.Errorf("expected exit 3 after %q, got %v %q (%v)", "a", exit, stdout, err)
+	}
+
+	if _, err := exec.LookPath("sh"); err == nil {
+		_, stdout, err = run(`let r = os.exec("sh", ["-c", "cat; echo $X >&2; pwd; exit 2"], {"stdin": "in ", "env": {"X": "x"}}); io.print(r["stdout"], r["stderr"], r["code"]);`,
+			WithPolicy(stdlib.Policy{Allow: []string{"os.exec", "io"}, FS: common.FSAccess{Root: root}}))
+		real, _ := filepath.EvalSymlinks(root)
+		expect := "in " + real + "\nx\n2"
+		if err != nil || stdout != expect {
+			t.Errorf("expected %q, got %q (%v)", expect, stdout, err)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		policy stdlib.Policy
+		expect string
+	}{
+		{`os.exit("1");`, stdlib.Policy{}, "exit expects argument to be int, got=string"},
+		{`os.exit(256);`, stdlib.Policy{}, "exit expects code to be between 0 and 255, got=256"},
+		{`os.exit(-1);`, stdlib.Policy{}, "exit expects code to be between 0 and 255, got=-1"},
+		{`os.setenv("A=B", "1");`, stdlib.Policy{}, `setenv: invalid variable name "A=B"`},
+		{`os.exec("rolang-missing-command");`, stdlib.Policy{}, `exec "rolang-missing-command": executable file not found in $PATH`},
+		{`os.exec("sh", [1]);`, stdlib.Policy{}, "exec expects arguments to be strings, got=int"},
+		{`os.exec("sh", [], {"cwd": "."});`, stdlib.Policy{}, "exec got unknown option cwd"},
+		{`os.exec("ls");`, stdlib.Policy{Deny: []string{"os.exec"}}, "permission denied: os.exec"},
+		{`os.args;`, stdlib.Policy{Deny: []string{"os"}}, "permission denied: os.args"},
+		{`os.exec("ls", [], {"dir": ".."});`, stdlib.Policy{Allow: []string{"os.exec"}, FS: common.FSAccess{Root: root}},
+			`permission denied: exec "..": path is outside of ` + strconv.Quote(root)},
+		{`os.exec("ls");`, stdlib.Policy{FS: common.FSAccess{Root: root}}, "permission denied: os.exec"},
+		{`os.exec("ls");`, stdlib.Policy{Allow: []string{"os"}, FS: common.FSAccess{ReadOnly: true}}, "permission denied: os.exec"},
+	}
+
+	for i, test := range errTests {
+		_, _, err := run(test.input, WithPolicy(test.policy))
+		if err == nil {
+			t.Fatalf("test[%d]: expected an error", i)
+		}
+		if !testErrors(t, err.Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestMapOrder(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{`io.print({"b": 1, "a": 2, 3: 3, true: 4});`, "{b: 1, a: 2, 3: 3, true: 4}"},
+		{`let m = {"a": 1, "b": 2, "c": 3}; m["a"] = 4; io.print(m);`, "{a: 4, b: 2, c: 3}"},
+		{`let m = {"a": 1, "b": 2, "c": 3}; maps.erase(m, "a"); m["a"] = 5; io.print(m);`, "{b: 2, c: 3, a: 5}"},
+		{`let m = {}; let i = 0; loop i < 100 { m[i] = i; i = i + 1; } i = 0; loop i < 98 { maps.erase(m, i); i = i + 1; } m[0] = 0; io.print(m, m[99]);`, "{98: 98, 99: 99, 0: 0}99"},
+		{`io.print({"x": 1, "y": 2} + {"z": 3, "x": 4});`, "{x: 4, y: 2, z: 3}"},
+		{`io.print(maps.concat({"b": 1}, {"a": 2}, {"b": 3}));`, "{b: 3, a: 2}"},
+		{`io.print(maps.copy({"b": 1, "a": 2}));`, "{b: 1, a: 2}"},
+		{`let m = {"b": 1, "a": 2}; io.print(maps.keys(m), maps.values(m), maps.entries(m));`, "[b, a][1, 2][[b, 1], [a, 2]]"},
+		{`io.print(maps.sortedKeys({"b": 0, 2.5: 0, "a": 0, 10: 0, true: 0, math.maxInt + 1: 0, false: 0, -1: 0}));`, "[false, true, -1, 2.5, 10, 9223372036854775808, a, b]"},
+		{`io.print(type(maps.keys({math.maxInt + 1: 0})[0] + 1));`, "int"},
+		{`io.print({"a": 1, "b": 2} == {"b": 2, "a": 1});`, "true"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, test.input, "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errs := testEvalStatements(t, "maps.keys([1]);")
+	testErrors(t, errors.Join(errs...).Error(), "keys expects argument to be map, got=array")
+}
+
+func TestMapsModule(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{`let m = {"a": 1}; io.print(maps.has(m, "a"), maps.has(m, "b"));`, "truefalse"},
+		{`let m = {"a": 1}; io.print(maps.get(m, "a", 0), maps.get(m, "b", 0), maps.get(m, "b"));`, "10null"},
+		{`let m = {"a": 1}; maps.set(m, "a", 2); maps.set(m, "b", 3); io.print(m);`, "{a: 2, b: 3}"},
+		{`io.print(maps.fromEntries([["b", 1], ["a", 2], ["b", 3]]));`, "{b: 3, a: 2}"},
+		{`let m = {"a": 1, "b": 2}; io.print(maps.fromEntries(maps.entries(m)) == m);`, "true"},
+		{`io.print(maps.mapValues({"a": 1, "b": 2}, fn(v) { return v * 10; }));`, "{a: 10, b: 20}"},
+		{`io.print(maps.mapValues({"a": 1}, fn(v, k) { return k + str(v); }));`, "{a: a1}"},
+		{`io.print(maps.filter({"a": 1, "b": 2, "c": 3}, fn(v) { return v != 2; }));`, "{a: 1, c: 3}"},
+		{`io.print(maps.filter({"a": 1, "b": 2}, fn(v, k) { return k == "b"; }));`, "{b: 2}"},
+		{`io.print(maps.merge({"a": 1, "b": 2}, {"b": 3, "c": 4}));`, "{a: 1, b: 3, c: 4}"},
+		{`io.print(maps.merge({"a": 1, "b": 2}, {"b": 3}, {"b": 4}, fn(x, y) { return x + y; }));`, "{a: 1, b: 9}"},
+		{`io.print(maps.merge({"a": 1}, {"a": 2}, fn(x, y, k) { return k; }));`, "{a: a}"},
+		{`let a = {"x": {"y": 1, "z": 2}, "w": 0}; let b = {"x": {"z": 3}, "w": {"v": 1}}; io.print(maps.deepMerge(a, b), a);`, "{x: {y: 1, z: 3}, w: {v: 1}}{x: {y: 1, z: 2}, w: 0}"},
+		{`io.print(maps.invert({"a": 1, "b": 2, "c": 1}));`, "{1: c, 2: b}"},
+		{`io.print(maps.pick({"a": 1, "b": 2, "c": 3}, ["c", "a", "d"]));`, "{c: 3, a: 1}"},
+		{`io.print(maps.omit({"a": 1, "b": 2, "c": 3}, ["b", "d"]));`, "{a: 1, c: 3}"},
+		{`let m = {"a": 1}; maps.mapValues(m, fn(v) { m["b"] = 2; return v; }); io.print(m);`, "{a: 1, b: 2}"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, test.input, "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{`maps.has({}, [fn() {}]);`, "only int, float, string, bool and array is allowed as key. got=function"},
+		{`maps.set({}, {}, 1);`, "only int, float, string, bool and array is allowed as key. got=map"},
+		{`let m = {[{}]: 2};`, "only int, float, string, bool and array is allowed as key. got=map"},
+		{`maps.get([], 1);`, "get expects first argument to be map, got=array"},
+		{`maps.fromEntries([["a", 1], ["b"]]);`, "fromEntries expects entries to be [key, value] pairs, entry=1 got=array"},
+		{`maps.invert({"a": {}});`, "invert: only int, float, string, bool and array is allowed as key. got=map"},
+		{`maps.pick({}, [null]);`, "only int, float, string, bool and array is allowed as key. got=null"},
+		{`maps.filter({}, 1);`, "filter expects second argument to be function, got=int"},
+		{`maps.merge({}, 1);`, "merge expects all arguments to be map, arg=2 got=int"},
+		{`maps.merge(fn(x, y) { return x; });`, "merge expects at least one map"},
+		{`let a = {}; a["a"] = a; maps.deepMerge(a, a);`, "deepMerge cannot merge a map containing itself"},
+		{`maps.mapValues({"a": 1}, fn(v) { return v - "x"; });`, "subtraction not supported for int and string"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestCollections(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{`let s = sets.new([3, 1, 3, 2]); io.print(s, sets.len(s), type(s));`, "set[3, 1, 2]3set"},
+		{`let s = sets.new(); io.print(sets.add(s, "a"), sets.add(s, "a"), sets.has(s, "a"), sets.remove(s, "a"), sets.remove(s, "a"), s);`, "truefalsetruetruefalseset[]"},
+		{`let a = sets.new([1, 2, 3]); let b = sets.new([2, 3, 4]); io.print(a | b, a & b, a - b, b - a);`, "set[1, 2, 3, 4]set[2, 3]set[1]set[4]"},
+		{`let a = sets.new([1, 2]); io.print(sets.union(a, sets.new([3]), sets.new([1, 4])), sets.intersection(a, sets.new([2])), sets.isSubset(sets.new([2]), a));`, "set[1, 2, 3, 4]set[2]true"},
+		{`io.print(2 in sets.new([1, 2]), 5 in sets.new([1, 2]), sets.new([1, 2]) == sets.new([2, 1]), sets.new([1]) == [1]);`, "truefalsetruefalse"},
+		{`io.print(2 in [1, 2], "a" in {"a": 1}, "b" in {"a": 1}, "ell" in "hello", 3 in deques.new([1, 2]));`, "truetruefalsetruefalse"},
+		{`io.print(arrays.map(sets.toArray(sets.new([1, 2])), fn(x) { return x * 2; }));`, "[2, 4]"},
+		{`let d = deques.new([2]); deques.pushBack(d, 3); deques.pushFront(d, 1); io.print(d, d[0], d[2], deques.len(d), type(d));`, "deque[1, 2, 3]133deque"},
+		{`let d = deques.new(); let i = 0; loop i < 20 { deques.pushBack(d, i); deques.pushFront(d, -i); i = i + 1; } io.print(deques.popFront(d), deques.popBack(d), deques.peekFront(d), deques.peekBack(d), deques.len(d));`, "-1919-181838"},
+		{`let d = deques.new(); let i = 0; loop i < 10 { deques.pushBack(d, i); deques.popFront(d); i = i + 1; } deques.pushBack(d, "x"); io.print(deques.toArray(d));`, "[x]"},
+		{`io.print(deques.new([1, 2]) == deques.new([1, 2]), deques.new([1]) == deques.new([2]));`, "truefalse"},
+		{`let h = heaps.new([5, 1, 4]); heaps.push(h, 2); heaps.push(h, 3); io.print(heaps.peek(h), heaps.len(h), heaps.toArray(h), heaps.pop(h), heaps.pop(h), heaps.len(h), type(h));`, "15[1, 2, 3, 4, 5]123heap"},
+		{`let h = heaps.new(fn(a, b) { return b - a; }); heaps.push(h, 1); heaps.push(h, 3); heaps.push(h, 2); io.print(heaps.pop(h), heaps.pop(h), heaps.pop(h));`, "321"},
+		{`let h = heaps.new([["b", 2], ["a", 1]], fn(x, y) { return x[1] - y[1]; }); io.print(heaps.pop(h)[0]);`, "a"},
+		{`let h = heaps.new(); io.print(h == h, h == heaps.new());`, "truefalse"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, test.input, "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{`sets.new([[null]]);`, "only int, float, string, bool and array is allowed as key. got=null"},
+		{`let b = {} in sets.new();`, "only int, float, string, bool and array is allowed as key. got=map"},
+		{`sets.new() | [1];`, "union not supported for set and array"},
+		{`[1] & [1];`, "intersection not supported for array and array"},
+		{`1 in 2;`, "membership not supported for int"},
+		{`1 in "a";`, "membership in string expects a string, got=int"},
+		{`deques.popFront(deques.new());`, "popFront from an empty deque"},
+		{`deques.new([1])[1];`, "index out of range [1]"},
+		{`heaps.pop(heaps.new());`, "pop from an empty heap"},
+		{`let h = heaps.new(); heaps.push(h, 1); heaps.push(h, "a");`, "cannot compare types string and int"},
+		{`heaps.new([1, 2], fn(a, b) { return true; });`, "heap expects comparator to return a number, got=bool"},
+		{`sets.len({});`, "len expects argument to be set, got=map"},
+		{`let h = heaps.new(fn(a, b) { heaps.pop(h); return a - b; }); heaps.push(h, 1); heaps.push(h, 2);`,
+			"pop cannot change a heap while it compares elements"},
+		{`let h = heaps.new(fn(a, b) { heaps.push(h, 0); return a - b; }); heaps.push(h, 1); heaps.push(h, 2);`,
+			"push cannot change a heap while it compares elements"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+
+	// a failing comparator leaves the heap as it was
+	var stdout bytes.Buffer
+	e := New(WithStdout(&stdout))
+	run := func(input string) []error {
+		program, errs := parser.New(lexer.New("evaluator_test", input)).Parse()
+		checkErrors(t, errs)
+		_, errs = e.Evaluate(program)
+		return errs
+	}
+	checkErrors(t, run(`let failOn = null; let h = heaps.new([1, 2, 3, 4, 5, 6, 7, 8, 9, 10], fn(a, b) { if failOn in [a, b] { return null; } return a - b; });`))
+	// both fail after moving elements
+	if errs := run(`failOn = 1; heaps.push(h, 0);`); len(errs) == 0 {
+		t.Fatalf("expected push to fail")
+	}
+	if errs := run(`failOn = 4; heaps.pop(h);`); len(errs) == 0 {
+		t.Fatalf("expected pop to fail")
+	}
+	checkErrors(t, run(`failOn = null; io.print(heaps.len(h), h); io.print(heaps.pop(h), heaps.pop(h));`))
+	if expect := "10heap[1, 2, 3, 4, 5, 6, 7, 8, 9, 10]12"; stdout.String() != expect {
+		t.Errorf("expected %q, got %q", expect, stdout.String())
+	}
+}
+
+func TestDeepEquality(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{`io.print([[1]] == [[1]], [[1]] == [[2]], [1, [2, [3]]] == [1, [2, [3.0]]], [[1]] != [[1, 2]]);`, "truefalsetruetrue"},
+		{`io.print({"a": [1], "b": {"c": 2}} == {"b": {"c": 2}, "a": [1]}, {"a": [1]} == {"a": [2]});`, "truefalse"},
+		{`io.print(deques.new([[1]]) == deques.new([[1]]), sets.new([[1], [2]]) == sets.new([[2], [1]]));`, "truetrue"},
+		{`let f = fn() {}; io.print(f == f, f == fn() {}, [f] == [f], io.print == io.print, io.print == io.println);`, "truefalsetruetruefalse"},
+		{`let a = [1]; arrays.push(a, a); let b = [1]; arrays.push(b, b); io.print(a == b, a == a);`, "truetrue"},
+		{`let m = {}; m["self"] = m; let n = {}; n["self"] = n; io.print(m == n);`, "true"},
+		{`let m = {[1, 2]: "a", [1]: "b"}; m[[1]] = "c"; io.print(m[[1, 2]], m, maps.len(m));`, "a{[1, 2]: a, [1]: c}2"},
+		{`let k = [1]; let m = {}; m[k] = 1; arrays.push(k, 2); io.print(m, [1] in m, k in m);`, "{[1]: 1}truefalse"},
+		{`let m = {[1]: 1}; let k = maps.keys(m)[0]; arrays.push(k, 2); io.print(m, m[[1]]);`, "{[1]: 1}1"},
+		{`let s = sets.new([[1], [1], [2]]); io.print(s, [2] in s, sets.len(s));`, "set[[1], [2]]true2"},
+		{`io.print(maps.sortedKeys({[2]: 1, [1, 5]: 2, "a": 3, [1]: 4}));`, "[a, [1], [1, 5], [2]]"},
+		{`io.print(hash([1, "a"]) == hash([1, "a"]), hash([1]) == hash(["1"]), hash(1) == hash(1), type(hash("a")));`, "truefalsetrueint"},
+		{`io.print(hash([1]) == hash([1.0]), hash(1) == hash(1.0), hash(0.5) == hash(0), hash(math.maxInt + 1) == hash(9223372036854775808.0));`, "truetruefalsetrue"},
+		{`let m = {[1]: 1, [1.0]: 2, 1: 3, 1.0: 4, 2.5: 5}; io.print(maps.len(m), m[[1]], m[1], m[2.5]);`, "3245"},
+		{`io.print(sets.len(sets.new([math.nan, math.nan, [math.nan], [math.nan]])), math.nan in sets.new([math.nan]));`, "2true"},
+		{`io.print([1, 2] < [1, 3], [1, 2] < [1, 2], [1] < [1, 0], [2] > [1, 5], [] < [1], [[1, 2]] < [[1, 3]]);`, "truefalsetruetruetruetrue"},
+		{`io.print([1, "a"] < [2, 5], ["a"] < ["b"], [1, 2] >= [1, 2], [3] <= [2, 9]);`, "truetruetruefalse"},
+		{`io.print(compare([2], [1, 5]), compare([1], [1.0]), compare(1, 2), compare("b", "a"), compare(math.maxInt + 1, 1));`, "10-111"},
+		{`let a = [1]; arrays.push(a, a); let b = [1]; arrays.push(b, b); io.print(a < b, a > b, compare(a, b));`, "falsefalse0"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, test.input, "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{`[1] < ["a"];`, "cannot compare types int and string"},
+		{`[1] < 1;`, "cannot compare types array and int"},
+		{`compare({}, {});`, "comparison not supported for map"},
+		{`compare(1);`, "compare expects two arguments, got=1"},
+		{`hash({});`, "hash: only int, float, string, bool and array is allowed as key. got=map"},
+		{`let a = [1]; arrays.push(a, a); let m = {}; m[a] = 1;`, "cannot use an array containing itself as key"},
+		{`let m = {[[1], {}]: 1};`, "only int, float, string, bool and array is allowed as key. got=map"},
+		{`let a = []; let b = []; let i = 0; loop i < 10001 { a = [a]; b = [b]; i = i + 1; } a == b;`, "maximum nesting depth exceeded (10000 levels)"},
+		{`let a = []; let b = []; let i = 0; loop i < 10001 { a = [a]; b = [b]; i = i + 1; } a < b;`, "maximum nesting depth exceeded (10000 levels)"},
+		{`let a = []; let b = []; let i = 0; loop i < 10001 { a = [a]; b = [b]; i = i + 1; } let m = {}; m[a] = 1;`, "maximum nesting depth exceeded (10000 levels)"},
+		{`let a = []; let b = []; let i = 0; loop i < 10001 { a = [a]; b = [b]; i = i + 1; } hash(a);`, "hash: maximum nesting depth exceeded (10000 levels)"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestJsonModule(t *testing.T) {
+	tests := []struct {
+		input  string
+		stdin  string
+		expect string
+	}{
+		{`let v = json.parse(io.readln()); io.print(v["a"], v["b"]["c"], type(v["b"]));`, `{"a": [1, 2.5, true, null], "b": {"c": "x"}}`, "[1, 2.5, true, null]xmap"},
+		{`io.print(arrays.map(json.parse("[1, 1.0, 1e2, 12345678901234567890]"), type));`, "", "[int, float, float, int]"},
+		{`io.print(json.parse(io.readln()) + 1);`, "12345678901234567890", "12345678901234567891"},
+		{`io.print(json.parse(io.readln())["k"]);`, `{"k": 1, "k": 2}`, "2"},
+		{`io.print(json.stringify({"b": [1, 2.0, null], "a": "x<y", "c": {}}));`, "", `{"b":[1,2.0,null],"a":"x<y","c":{}}`},
+		{`io.print(json.stringify([1, {"a": true}], {"indent": 2}));`, "", "[\n  1,\n  {\n    \"a\": true\n  }\n]"},
+		{`io.print(json.stringify({"a": []}, {"indent": "	"}));`, "", "{\n\t\"a\": []\n}"},
+		{`let s = json.stringify(json.parse(io.readln())); io.print(s);`, `{"q": "a\"b\\c\u00e9", "n": -0.5e-10}`, `{"q":"a\"b\\cé","n":-5e-11}`},
+		{`let v = [1]; io.print(json.stringify([v, v]));`, "", "[[1],[1]]"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, test.input, test.stdin)
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{`json.parse("[1, 2");`, "parse: unexpected end of JSON input at line 1, column 6"},
+		{`json.parse("[1,
+  2,]");`, "parse: invalid character ']' looking for beginning of value at line 2, column 5"},
+		{`json.parse("[1] 2");`, "parse: invalid character '2' after top-level value at line 1, column 5"},
+		{`json.parse(1);`, "parse expects argument to be string, got=int"},
+		{`let a = [1]; arrays.push(a, a); json.stringify(a);`, "stringify: cannot encode an array containing itself"},
+		{`let m = {}; m["m"] = m; json.stringify([m]);`, "stringify: cannot encode a map containing itself"},
+		{`json.stringify({1: 2});`, "stringify: cannot encode map key 1 of type int, json keys must be strings"},
+		{`json.stringify([fn() {}]);`, "stringify: cannot encode value of type function"},
+		{`json.stringify(math.nan);`, "stringify: cannot encode NaN, json has no such number"},
+		{`json.stringify(1, {"pretty": true});`, "stringify got unknown option pretty"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
+func TestRegexModule(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{`let re = regex.compile("[a-z]+"); io.print(re, type(re), regex.match(re, "12ab"), regex.match(re, "12"));`, `<regex "[a-z]+">regextruefalse`},
+		{`io.print(regex.find("\d+", "ab 12 34"), regex.find("\d+", "ab"));`, "12null"},
+		{`io.print(regex.findAll("\d+", "1 22 333"), regex.findAll("\d+", "1 22 333", 2), regex.findAll("x", "abc"));`, "[1, 22, 333][1, 22][]"},
+		{`io.print(regex.captures("(?P<key>\w+)=(?P<value>\w+)", "level=warn"));`, "{0: level=warn, 1: level, key: level, 2: warn, value: warn}"},
+		{`io.print(regex.captures("(a)|(b)", "b"), regex.captures("a", "b"));`, "{0: b, 1: null, 2: b}null"},
+		{`io.print(regex.replace("(?P<k>\w+)=(\d+)", "a=1 b=2", "$2:${k}"));`, "1:a 2:b"},
+		{`io.print(regex.replace("\d+", "a1b22", fn(m) { return "<" + m + ">"; }));`, "a<1>b<22>"},
+		{`io.print(regex.replace("(?P<k>\w+)=\d+", "a=1 b=2", fn(m, g) { return strings.upper(g["k"]); }));`, "A B"},
+		{`io.print(regex.replace("[a-z]+", "ab 12 cd", strings.upper));`, "AB 12 CD"},
+		{`io.print(regex.split("[,;] *", "a, b;c"), regex.split(",", "a,b,c", 2));`, "[a, b, c][a, b,c]"},
+		{`io.print(regex.compile("a+") == regex.compile("a+"), regex.compile("a+") == regex.compile("b+"));`, "truefalse"},
+	}
+
+	for i, test := range tests {
+		stdout, _ := testEvalOutput(t, test.input, "")
+		if stdout != test.expect {
+			t.Errorf("test[%d]: expected %q, got %q", i, test.expect, stdout)
+		}
+	}
+
+	errTests := []struct {
+		input  string
+		expect string
+	}{
+		{`regex.compile("a(b");`, `compile: missing closing ) at column 4 of "a(b"`},
+		{`regex.compile("ab)c");`, `compile: unexpected ) at column 3 of "ab)c"`},
+		{`regex.compile("é)");`, `compile: unexpected ) at column 2 of "é)"`},
+		{`regex.compile("[)(]a)");`, `compile: unexpected ) at column 6 of "[)(]a)"`},
+		{`regex.compile("[]a)]b)");`, `compile: unexpected ) at column 7 of "[]a)]b)"`},
+		{`regex.compile("[[:alpha:])]x)");`, `compile: unexpected ) at column 14 of "[[:alpha:])]x)"`},
+		{`regex.compile("é(b");`, `compile: missing closing ) at column 4 of "é(b"`},
+		{`regex.compile("éx[z-a]");`, `compile: invalid character class range at column 4 of "éx[z-a]"`},
+		{`regex.compile("x[z-a]");`, `compile: invalid character class range at column 3 of "x[z-a]"`},
+		{`regex.match("a**", "a");`, `match: compile: invalid nested repetition operator at column 2 of "a**"`},
+		{`regex.match(1, "a");`, "match expects first argument to be regex or string, got=int"},
+		{`regex.find("a");`, "find expects two arguments, got=1"},
+		{`regex.findAll("a", "a", "b");`, "findAll expects third argument to be int, got=string"},
+		{`regex.replace("a", "a", fn(m) { return 1; });`, "replace expects function to return string, got=int"},
+		{`regex.replace("a", "a", 1);`, "replace expects third argument to be string or function, got=int"},
+	}
+
+	for i, test := range errTests {
+		errs := testEvalStatements(t, test.input)
+		if !testErrors(t, errors.Join(errs...).Error(), test.expect) {
+			t.Logf("test[%d]\n", i)
+		}
+	}
+}
+
 func TestOutStatements(t *testing.T) {
+	tests := []struct {
+		input  string
+		stdout string
+		stderr string
+	}{
+		{`io.print("a", 1, 2.5);`, "a12.5", ""},
+		{`io.println("hello"); io.println();`, "hello\n\n", ""},
+		{`io.println([1, "a"]);`, "[1, a]\n", ""},
+		{`io.eprintln("oops"); io.print("ok");`, "ok", "oops\n"},
+		{`let x = 0; loop x < 3 { io.print(x); x = x + 1; }`, "012", ""},
+	}
+
+	for i, test := range tests {
+		stdout, stderr := testEvalOutput(t, test.input, "")
+		if stdout != test.stdout || stderr != test.stderr {
+			t.Errorf("test[%d]: expected stdout %q stderr %q, got %q %q",
+				i, test.stdout, test.stderr, stdout, stderr)
+		}
+	}
+}
+
+func TestInStatements(t *testing.T) {
+	input := `
+		let name = io.readln();
+		let line = io.readln();
+		io.println("hi " + name + ", " + line);
+		io.println(io.readln());
+		io.println(io.readln() == null);
+	`
+
+	stdout, _ := testEvalOutput(t, input, "ro\r\nsecond line\nlast")
+	expect := "hi ro, second line\nlast\ntrue\n"
+	if stdout != expect {
+		t.Errorf("expected %q, got %q", expect, stdout)
+	}
+}
+
+// evaluates the statements with the given input
+// and returns what was printed to stdout and stderr
+func testEvalOutput(t *testing.T, input string, stdin string) (string, string) {
+	l := lexer.New("evaluator_test", input)
+	p := parser.New(l)
+
+	var stdout, stderr bytes.Buffer
+	e := New(WithStdin(strings.NewReader(stdin)), WithStdout(&stdout), WithStderr(&stderr))
+
+	program, errs := p.Parse()
+	checkErrors(t, errs)
+
+	_, errs = e.Evaluate(program)
+	checkErrors(t, errs)
 
+	return stdout.String(), stderr.String()
 }
 
 func testLetStatements(t *testing.T, input string, expects []expectType) bool {
@@ -350,7 +1652,8 @@ func testEvalStatements(t *testing.T, input string) []error {
 	program, errs := p.Parse()
 	checkErrors(t, errs)
 
-	return e.Evaluate(program)
+	_, errs = e.Evaluate(program)
+	return errs
 }
 
 func testEvalExpression(t *testing.T, input string) any {
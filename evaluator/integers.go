@@ -0,0 +1,124 @@
+package evaluator
+
+import (
+	"RoLang/evaluator/objects"
+
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// integers are int64 values which are promoted to big integers
+// whenever an operation overflows. big integers that fit back into
+// an int64 are always demoted so every integer has one representation
+
+func bigToFloat(value *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(value).Float64()
+	return f
+}
+
+// widen prepares the operands of an arithmetic or comparison operator.
+// if either operand is a big integer then an integer partner is widened to
+// a big integer as well, while a float partner turns both into floats
+func widen(left, right any) (any, any) {
+	switch l := left.(type) {
+	case *big.Int:
+		switch r := right.(type) {
+		case int64:
+			return l, big.NewInt(r)
+		case float64:
+			return bigToFloat(l), r
+		}
+	case int64:
+		if r, ok := right.(*big.Int); ok {
+			return big.NewInt(l), r
+		}
+	case float64:
+		if r, ok := right.(*big.Int); ok {
+			return l, bigToFloat(r)
+		}
+	}
+
+	return left, right
+}
+
+func (e *Evaluator) overflow(op string, l, r int64, result func() *big.Int) (any, error) {
+	if e.checkedOverflow {
+		return nil, fmt.Errorf("integer overflow in %d %s %d", l, op, r)
+	}
+
+	return objects.NormalizeBig(result()), nil
+}
+
+func (e *Evaluator) addInt(l, r int64) (any, error) {
+	sum := l + r
+	// overflow happened if both operands have the same
+	// sign but the sum has a different one
+	if (l >= 0) == (r >= 0) && (sum >= 0) != (l >= 0) {
+		return e.overflow("+", l, r, func() *big.Int {
+			return new(big.Int).Add(big.NewInt(l), big.NewInt(r))
+		})
+	}
+
+	return sum, nil
+}
+
+func (e *Evaluator) subInt(l, r int64) (any, error) {
+	diff := l - r
+	if (l >= 0) != (r >= 0) && (diff >= 0) != (l >= 0) {
+		return e.overflow("-", l, r, func() *big.Int {
+			return new(big.Int).Sub(big.NewInt(l), big.NewInt(r))
+		})
+	}
+
+	return diff, nil
+}
+
+func (e *Evaluator) mulInt(l, r int64) (any, error) {
+	if l == 0 || r == 0 {
+		return int64(0), nil
+	}
+
+	prod := l * r
+	if prod/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) {
+		return e.overflow("*", l, r, func() *big.Int {
+			return new(big.Int).Mul(big.NewInt(l), big.NewInt(r))
+		})
+	}
+
+	return prod, nil
+}
+
+func (e *Evaluator) divInt(l, r int64) (any, error) {
+	if r == 0 {
+		return nil, fmt.Errorf("integer division by zero")
+	}
+
+	if l == math.MinInt64 && r == -1 {
+		return e.overflow("/", l, r, func() *big.Int {
+			return new(big.Int).Neg(big.NewInt(l))
+		})
+	}
+
+	return l / r, nil
+}
+
+func (e *Evaluator) negInt(v int64) (any, error) {
+	if v == math.MinInt64 {
+		if e.checkedOverflow {
+			return nil, fmt.Errorf("integer overflow in -(%d)", v)
+		}
+		return new(big.Int).Neg(big.NewInt(v)), nil
+	}
+
+	return -v, nil
+}
+
+func bigDiv(l, r *big.Int) (any, error) {
+	if r.Sign() == 0 {
+		return nil, fmt.Errorf("integer division by zero")
+	}
+
+	// truncated division to match int64 semantics
+	return objects.NormalizeBig(new(big.Int).Quo(l, r)), nil
+}
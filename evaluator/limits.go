@@ -0,0 +1,108 @@
+package evaluator
+
+import (
+	"RoLang/evaluator/objects"
+
+	"context"
+	"errors"
+	"fmt"
+)
+
+// errors raised when a script goes over one of the execution limits,
+// they are wrapped with the location where the limit was hit
+var (
+	ErrStepLimit    = errors.New("step limit exceeded")
+	ErrCallDepth    = errors.New("maximum call depth exceeded")
+	ErrElementLimit = errors.New("element limit exceeded")
+)
+
+// calls nest natively, so deep recursion would overflow the Go
+// stack long before a script notices anything is wrong
+const defaultMaxCallDepth = 10_000
+
+type limits struct {
+	ctx context.Context
+	// 0 means no limit
+	maxSteps    int64
+	maxDepth    int
+	maxElements int64
+
+	steps    int64
+	depth    int
+	elements int64
+}
+
+// resets the usage counters at the start of every evaluation
+func (l *limits) reset() {
+	l.steps = 0
+	l.depth = 0
+	l.elements = 0
+}
+
+// counts one evaluated node against the step budget
+func (l *limits) step() error {
+	l.steps++
+	if l.maxSteps > 0 && l.steps > l.maxSteps {
+		return fmt.Errorf("%w (%d steps)", ErrStepLimit, l.maxSteps)
+	}
+
+	return nil
+}
+
+// reports if the context was cancelled or ran past its deadline
+func (l *limits) checkContext() error {
+	if err := l.ctx.Err(); err != nil {
+		return fmt.Errorf("execution stopped: %w", err)
+	}
+
+	return nil
+}
+
+func (l *limits) enterCall() error {
+	if l.maxDepth > 0 && l.depth >= l.maxDepth {
+		return fmt.Errorf("%w (%d calls)", ErrCallDepth, l.maxDepth)
+	}
+	l.depth++
+
+	return nil
+}
+
+func (l *limits) exitCall() {
+	l.depth--
+}
+
+// counts newly allocated array and map elements
+func (l *limits) allocate(n int64) error {
+	if n <= 0 {
+		return nil
+	}
+
+	l.elements += n
+	if l.maxElements > 0 && l.elements > l.maxElements {
+		return fmt.Errorf("%w (%d elements)", ErrElementLimit, l.maxElements)
+	}
+
+	return nil
+}
+
+// approximates the number of elements held by the
+// arrays and maps among the values, without recursing
+func elementCount(values ...any) int64 {
+	var n int64
+	for _, value := range values {
+		switch v := value.(type) {
+		case *objects.ArrayObject:
+			n += v.Len()
+		case *objects.MapObject:
+			n += v.Len()
+		case *objects.SetObject:
+			n += v.Len()
+		case *objects.DequeObject:
+			n += v.Len()
+		case *objects.HeapObject:
+			n += v.Len()
+		}
+	}
+
+	return n
+}
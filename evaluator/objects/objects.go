@@ -5,7 +5,17 @@ import (
 	"RoLang/evaluator/env"
 	"fmt"
 
+	"bufio"
+	"cmp"
+	"errors"
+	"iter"
+	"math"
+	"math/big"
+	"os"
+	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 )
 
 type (
@@ -22,8 +32,59 @@ type (
 	ArrayObject struct {
 		List []any
 	}
+	// MapObject keeps its entries in insertion order. Keys indexes
+	// the entries by the hashed key, erased entries are left as holes
+	// and dropped once they make up half of the entries. the zero
+	// value is an empty map
 	MapObject struct {
-		Map map[any]any
+		keys    map[any]int
+		entries []mapEntry
+		holes   int
+	}
+	mapEntry struct {
+		key    any
+		value  any
+		erased bool
+	}
+	// SetObject holds distinct values in insertion order, its
+	// elements are stored as the keys of a map
+	SetObject struct {
+		Elems MapObject
+	}
+	// DequeObject is a ring buffer, adding and removing
+	// elements at either end takes constant time
+	DequeObject struct {
+		buf  []any
+		head int
+		size int
+	}
+	// HeapObject is a binary heap kept in List. Compare is the
+	// function ordering the elements, or nil for `<`. Comparing
+	// is set while Compare runs, the heap must not change then
+	HeapObject struct {
+		List      []any
+		Compare   any
+		Comparing bool
+	}
+	// big integers are pointers, so they are stored
+	// in maps by their decimal representation
+	BigIntKey string
+	// arrays are mutable, so they are stored in maps by an
+	// encoding of their elements, equal arrays are encoded
+	// the same way
+	ArrayKey string
+	// FileObject is a file opened by the fs module. Path is the
+	// path as the script wrote it, Reader is nil unless the
+	// file was opened for reading
+	FileObject struct {
+		Path   string
+		File   *os.File
+		Reader *bufio.Reader
+		Closed bool
+	}
+	// RegexObject is a pattern compiled by the regex module
+	RegexObject struct {
+		Regexp *regexp.Regexp
 	}
 )
 
@@ -56,25 +117,466 @@ func (o *ArrayObject) Len() int64 {
 	return int64(len(o.List))
 }
 
+// NormalizeBig returns big integers which fit into an int64 as int64,
+// so that every integer has one representation
+func NormalizeBig(value *big.Int) any {
+	if value.IsInt64() {
+		return value.Int64()
+	}
+
+	return value
+}
+
+// MaxNesting bounds how deeply values may be nested for operations
+// which walk them natively, like comparing them or using them as
+// keys, so that deep values fail instead of overflowing the Go stack
+const MaxNesting = 10_000
+
+var ErrNesting = errors.New("maximum nesting depth exceeded")
+
+// the key of every NaN, so that NaN keys can be found again
+type nanKey struct{}
+
+// HashKey converts a value into the form used as a key in MapObject.
+// floats holding a whole number are the same key as the int they are
+// equal to, and all NaNs are one key
+func HashKey(key any) any {
+	switch v := key.(type) {
+	case *big.Int:
+		return BigIntKey(v.String())
+	case float64:
+		if math.IsNaN(v) {
+			return nanKey{}
+		}
+		if n, ok := integral(v); ok {
+			if n.IsInt64() {
+				return n.Int64()
+			}
+			return BigIntKey(n.String())
+		}
+	case *ArrayObject:
+		return ArrayKey(EncodeKey(v))
+	}
+
+	return key
+}
+
+// returns the integer a float is equal to, if it holds a whole number
+func integral(f float64) (*big.Int, bool) {
+	if math.IsInf(f, 0) || f != math.Trunc(f) {
+		return nil, false
+	}
+	if f >= math.MinInt64 && f < math.MaxInt64 {
+		return big.NewInt(int64(f)), true
+	}
+
+	n, _ := big.NewFloat(f).Int(nil)
+	return n, true
+}
+
+// EncodeKey returns a string which is the same for keys that are the
+// same in a map, the type is part of it so that 1 and "1" differ.
+// arrays must have passed the checks for keys, so they do not contain
+// themselves and are not nested deeper than MaxNesting
+func EncodeKey(key any) string {
+	var b strings.Builder
+	encodeKey(&b, key)
+	return b.String()
+}
+
+func encodeKey(b *strings.Builder, key any) {
+	switch v := key.(type) {
+	case int64:
+		b.WriteString("i" + strconv.FormatInt(v, 10) + ";")
+	case *big.Int:
+		b.WriteString("i" + v.String() + ";")
+	case float64:
+		if n, ok := integral(v); ok {
+			b.WriteString("i" + n.String() + ";")
+		} else {
+			b.WriteString("f" + strconv.FormatFloat(v, 'g', -1, 64) + ";")
+		}
+	case string:
+		b.WriteString("s" + strconv.Itoa(len(v)) + ":" + v)
+	case bool:
+		b.WriteString("b" + strconv.FormatBool(v) + ";")
+	case *ArrayObject:
+		b.WriteString("[")
+		for _, elem := range v.List {
+			encodeKey(b, elem)
+		}
+		b.WriteString("]")
+	default:
+		b.WriteString(fmt.Sprintf("?%T;", v))
+	}
+}
+
+// copies arrays used as keys, so that changing the
+// array afterwards does not change the key in the map
+func freezeKey(key any) any {
+	arr, ok := key.(*ArrayObject)
+	if !ok {
+		return key
+	}
+
+	list := make([]any, len(arr.List))
+	for i, elem := range arr.List {
+		list[i] = freezeKey(elem)
+	}
+	return &ArrayObject{List: list}
+}
+
+func (k BigIntKey) Int() *big.Int {
+	v, _ := new(big.Int).SetString(string(k), 10)
+	return v
+}
+
+func (o *MapObject) Get(key any) (any, bool) {
+	i, ok := o.keys[HashKey(key)]
+	if !ok {
+		return nil, false
+	}
+
+	return o.entries[i].value, true
+}
+
+// Set replaces the value of an existing key in its place,
+// new keys are added after all others
+func (o *MapObject) Set(key any, val any) {
+	hash := HashKey(key)
+	if i, ok := o.keys[hash]; ok {
+		o.entries[i].value = val
+		return
+	}
+
+	if o.keys == nil {
+		o.keys = make(map[any]int)
+	}
+	o.keys[hash] = len(o.entries)
+	o.entries = append(o.entries, mapEntry{key: freezeKey(key), value: val})
+}
+
 func (o *MapObject) Insert(key any, val any) bool {
-	if _, ok := o.Map[key]; ok {
+	if _, ok := o.keys[HashKey(key)]; ok {
 		return false
 	}
 
-	o.Map[key] = val
+	o.Set(key, val)
 	return true
 }
 
+// Erase removes a key and returns its value, a key set
+// again afterwards is added after all others
 func (o *MapObject) Erase(key any) any {
-	val, ok := o.Map[key]
-	if ok {
-		delete(o.Map, key)
-		return val
+	hash := HashKey(key)
+	i, ok := o.keys[hash]
+	if !ok {
+		return nil
 	}
 
-	return nil
+	val := o.entries[i].value
+	delete(o.keys, hash)
+	o.entries[i] = mapEntry{erased: true}
+	o.holes++
+	if o.holes > len(o.entries)/2 {
+		o.compact()
+	}
+
+	return val
+}
+
+func (o *MapObject) compact() {
+	entries := make([]mapEntry, 0, len(o.keys))
+	for _, entry := range o.entries {
+		if !entry.erased {
+			o.keys[HashKey(entry.key)] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+	o.entries = entries
+	o.holes = 0
 }
 
 func (o *MapObject) Len() int64 {
-	return int64(len(o.Map))
+	return int64(len(o.keys))
+}
+
+// All iterates over the keys and values in insertion order, the
+// map must not be changed while iterating. array keys are copies
+// so that changing them leaves the map as it is
+func (o *MapObject) All() iter.Seq2[any, any] {
+	return func(yield func(any, any) bool) {
+		for _, entry := range o.entries {
+			if !entry.erased && !yield(freezeKey(entry.key), entry.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns the keys in insertion order
+func (o *MapObject) Keys() []any {
+	keys := make([]any, 0, len(o.keys))
+	for key := range o.All() {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Values returns the values in the order of their keys
+func (o *MapObject) Values() []any {
+	values := make([]any, 0, len(o.keys))
+	for _, value := range o.All() {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// Clone returns a shallow copy of the map with the same order
+func (o *MapObject) Clone() *MapObject {
+	mp := &MapObject{}
+	for key, value := range o.All() {
+		mp.Set(key, value)
+	}
+
+	return mp
+}
+
+// CompareKeys orders map keys: bools first, false before true, then
+// numbers by value, then strings and then arrays element by element,
+// the same order for every map
+func CompareKeys(a, b any) int {
+	if rank(a) != rank(b) {
+		return cmp.Compare(rank(a), rank(b))
+	}
+
+	switch a := a.(type) {
+	case bool:
+		if a == b {
+			return 0
+		} else if !a {
+			return -1
+		}
+		return 1
+	case string:
+		return cmp.Compare(a, b.(string))
+	case *ArrayObject:
+		// element by element, a shorter array comes
+		// first when it is the start of the other one
+		return slices.CompareFunc(a.List, b.(*ArrayObject).List, CompareKeys)
+	default:
+		return compareNumbers(a, b)
+	}
+}
+
+func rank(key any) int {
+	switch key.(type) {
+	case bool:
+		return 0
+	case int64, *big.Int, BigIntKey, float64:
+		return 1
+	case string:
+		return 2
+	case *ArrayObject:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func compareNumbers(a, b any) int {
+	_, aFloat := a.(float64)
+	_, bFloat := b.(float64)
+	if !aFloat && !bFloat {
+		return toBigInt(a).Cmp(toBigInt(b))
+	}
+
+	// floats which are equal after rounding the ints are compared exactly
+	x, y := toFloat(a), toFloat(b)
+	if c := cmp.Compare(x, y); c != 0 || math.IsNaN(x) || math.IsNaN(y) {
+		return c
+	}
+
+	return toBigFloat(a).Cmp(toBigFloat(b))
+}
+
+func toBigInt(n any) *big.Int {
+	switch n := n.(type) {
+	case int64:
+		return big.NewInt(n)
+	case BigIntKey:
+		return n.Int()
+	default:
+		return n.(*big.Int)
+	}
+}
+
+func toFloat(n any) float64 {
+	if f, ok := n.(float64); ok {
+		return f
+	}
+
+	f, _ := toBigFloat(n).Float64()
+	return f
+}
+
+func toBigFloat(n any) *big.Float {
+	if f, ok := n.(float64); ok {
+		return new(big.Float).SetFloat64(f)
+	}
+
+	return new(big.Float).SetInt(toBigInt(n))
+}
+
+func (o *SetObject) Add(elem any) bool {
+	return o.Elems.Insert(elem, nil)
+}
+
+func (o *SetObject) Has(elem any) bool {
+	_, ok := o.Elems.Get(elem)
+	return ok
+}
+
+func (o *SetObject) Remove(elem any) bool {
+	if !o.Has(elem) {
+		return false
+	}
+
+	o.Elems.Erase(elem)
+	return true
+}
+
+func (o *SetObject) Len() int64 {
+	return o.Elems.Len()
+}
+
+// List returns the elements in insertion order
+func (o *SetObject) List() []any {
+	return o.Elems.Keys()
+}
+
+func (o *DequeObject) PushBack(elem any) {
+	o.grow()
+	o.buf[(o.head+o.size)%len(o.buf)] = elem
+	o.size++
+}
+
+func (o *DequeObject) PushFront(elem any) {
+	o.grow()
+	o.head = (o.head - 1 + len(o.buf)) % len(o.buf)
+	o.buf[o.head] = elem
+	o.size++
+}
+
+func (o *DequeObject) PopBack() (any, bool) {
+	if o.size == 0 {
+		return nil, false
+	}
+
+	i := (o.head + o.size - 1) % len(o.buf)
+	elem := o.buf[i]
+	o.buf[i] = nil
+	o.size--
+	return elem, true
+}
+
+func (o *DequeObject) PopFront() (any, bool) {
+	if o.size == 0 {
+		return nil, false
+	}
+
+	elem := o.buf[o.head]
+	o.buf[o.head] = nil
+	o.head = (o.head + 1) % len(o.buf)
+	o.size--
+	return elem, true
+}
+
+// At returns the element at index from the front
+func (o *DequeObject) At(index int) (any, bool) {
+	if index < 0 || index >= o.size {
+		return nil, false
+	}
+
+	return o.buf[(o.head+index)%len(o.buf)], true
+}
+
+func (o *DequeObject) Len() int64 {
+	return int64(o.size)
+}
+
+// List returns the elements from front to back
+func (o *DequeObject) List() []any {
+	list := make([]any, o.size)
+	for i := range list {
+		list[i] = o.buf[(o.head+i)%len(o.buf)]
+	}
+
+	return list
+}
+
+// doubles the buffer when it is full
+func (o *DequeObject) grow() {
+	if o.size < len(o.buf) {
+		return
+	}
+
+	buf := make([]any, max(2*len(o.buf), 8))
+	copy(buf, o.List())
+	o.buf = buf
+	o.head = 0
+}
+
+func (o *HeapObject) Len() int64 {
+	return int64(len(o.List))
+}
+
+// Union returns the elements of o followed by the elements of other not in o
+func (o *SetObject) Union(other *SetObject) *SetObject {
+	result := &SetObject{Elems: *o.Elems.Clone()}
+	for _, elem := range other.List() {
+		result.Add(elem)
+	}
+
+	return result
+}
+
+// Intersection returns the elements of o which are also in other
+func (o *SetObject) Intersection(other *SetObject) *SetObject {
+	result := &SetObject{}
+	for _, elem := range o.List() {
+		if other.Has(elem) {
+			result.Add(elem)
+		}
+	}
+
+	return result
+}
+
+// Difference returns the elements of o which are not in other
+func (o *SetObject) Difference(other *SetObject) *SetObject {
+	result := &SetObject{}
+	for _, elem := range o.List() {
+		if !other.Has(elem) {
+			result.Add(elem)
+		}
+	}
+
+	return result
+}
+
+// IsSubset reports whether every element of o is in other
+func (o *SetObject) IsSubset(other *SetObject) bool {
+	if o.Len() > other.Len() {
+		return false
+	}
+	for _, elem := range o.List() {
+		if !other.Has(elem) {
+			return false
+		}
+	}
+
+	return true
 }
@@ -0,0 +1,103 @@
+package evaluator
+
+import (
+	"RoLang/stdlib"
+
+	"context"
+	"io"
+)
+
+type Option func(*Evaluator)
+
+// WithTypeChecks enables checking of type annotations at runtime.
+// By default annotations are ignored by the evaluator and only
+// used by the static checker
+func WithTypeChecks() Option {
+	return func(e *Evaluator) {
+		e.typeChecks = true
+	}
+}
+
+// WithCheckedOverflow makes integer overflow a runtime error
+// instead of promoting the result to a big integer
+func WithCheckedOverflow() Option {
+	return func(e *Evaluator) {
+		e.checkedOverflow = true
+	}
+}
+
+// WithContext stops evaluation once the context is cancelled or
+// its deadline passes, it is checked on every loop iteration and call
+func WithContext(ctx context.Context) Option {
+	return func(e *Evaluator) {
+		e.limits.ctx = ctx
+	}
+}
+
+// WithMaxSteps limits the number of statements and expressions
+// a single call to Evaluate may evaluate
+func WithMaxSteps(steps int64) Option {
+	return func(e *Evaluator) {
+		e.limits.maxSteps = steps
+	}
+}
+
+// WithMaxCallDepth limits how deeply function calls may nest,
+// 0 removes the limit. The default is 10000
+func WithMaxCallDepth(depth int) Option {
+	return func(e *Evaluator) {
+		e.limits.maxDepth = depth
+	}
+}
+
+// WithMaxElements puts an approximate cap on the number of array and
+// map elements a single call to Evaluate may allocate
+func WithMaxElements(elements int64) Option {
+	return func(e *Evaluator) {
+		e.limits.maxElements = elements
+	}
+}
+
+// WithPolicy restricts the stdlib modules and functions
+// scripts may use, see stdlib.Policy
+func WithPolicy(policy stdlib.Policy) Option {
+	return func(e *Evaluator) {
+		e.config.Policy = policy
+	}
+}
+
+// WithSeed seeds the generator of the random module so
+// that scripts produce the same values on every run
+func WithSeed(seed int64) Option {
+	return func(e *Evaluator) {
+		e.config.Seed = &seed
+	}
+}
+
+// WithArgs sets the command line arguments scripts see as `os.args`
+func WithArgs(args ...string) Option {
+	return func(e *Evaluator) {
+		e.config.Args = args
+	}
+}
+
+// WithStdin sets the stream scripts read input from, os.Stdin by default
+func WithStdin(stdin io.Reader) Option {
+	return func(e *Evaluator) {
+		e.config.Stdin = stdin
+	}
+}
+
+// WithStdout sets the stream scripts print to, os.Stdout by default
+func WithStdout(stdout io.Writer) Option {
+	return func(e *Evaluator) {
+		e.config.Stdout = stdout
+	}
+}
+
+// WithStderr sets the stream scripts print errors to, os.Stderr by default
+func WithStderr(stderr io.Writer) Option {
+	return func(e *Evaluator) {
+		e.config.Stderr = stderr
+	}
+}
@@ -0,0 +1,41 @@
+package evaluator
+
+import (
+	"RoLang/token"
+
+	"fmt"
+	"strings"
+)
+
+// number of locations shown at each end of a long trace
+const traceEdge = 10
+
+// traceError is a runtime error together with the locations of the nodes
+// it unwound through. locations are collected innermost first and only
+// formatted when the error is printed, so errors raised deep inside a
+// recursion stay cheap to propagate
+type traceError struct {
+	locs []token.SrcLoc
+	err  error
+}
+
+func (t *traceError) Error() string {
+	var out strings.Builder
+
+	n := len(t.locs)
+	for i := n - 1; i >= 0; i-- {
+		// long traces keep the outermost and innermost locations
+		if n > 2*traceEdge && i == n-1-traceEdge {
+			fmt.Fprintf(&out, "\n... %d more ...", n-2*traceEdge)
+			i = traceEdge
+		}
+		fmt.Fprintf(&out, "\n%s ", t.locs[i])
+	}
+	out.WriteString(t.err.Error())
+
+	return out.String()
+}
+
+func (t *traceError) Unwrap() error {
+	return t.err
+}
@@ -0,0 +1,61 @@
+package evaluator
+
+import (
+	"RoLang/ast"
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+)
+
+// reports if a runtime value satisfies a type annotation, ints
+// are accepted where floats are expected like in arithmetic
+func matchesType(value any, typ ast.TypeExpr) bool {
+	switch t := typ.(type) {
+	case *ast.NamedType:
+		switch t.Name {
+		case "any":
+			return true
+		case "float":
+			switch value.(type) {
+			case int64, float64:
+				return true
+			}
+			return false
+		default:
+			return builtin.TypeStr(value) == t.Name
+		}
+	case *ast.ArrayType:
+		arr, ok := value.(*objects.ArrayObject)
+		if !ok {
+			return false
+		}
+		for _, elem := range arr.List {
+			if !matchesType(elem, t.Elem) {
+				return false
+			}
+		}
+		return true
+	case *ast.MapType:
+		mp, ok := value.(*objects.MapObject)
+		if !ok {
+			return false
+		}
+		for key, val := range mp.All() {
+			if !matchesType(key, t.Key) || !matchesType(val, t.Value) {
+				return false
+			}
+		}
+		return true
+	case *ast.UnionType:
+		for _, member := range t.Types {
+			if matchesType(value, member) {
+				return true
+			}
+		}
+		return false
+	case *ast.FunctionType:
+		// signatures of functions are not known at runtime
+		return builtin.TypeStr(value) == "function"
+	default:
+		return true
+	}
+}
@@ -1,3 +1,5 @@
 module RoLang
 
 go 1.23.4
+
+require golang.org/x/text v0.28.0
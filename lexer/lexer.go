@@ -3,15 +3,19 @@ package lexer
 import (
 	"RoLang/token"
 	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Lexer struct {
 	file   string
 	input  string
 	line   uint // current line number
-	col    uint // current column number
+	col    uint // current column number, counted in runes
+	pos    uint // byte position of current character
 	offset uint // next position to read
-	char   byte // current ASCII character
+	char   rune // current character
 }
 
 func New(file, input string) *Lexer {
@@ -35,6 +39,10 @@ func (l *Lexer) NextToken() token.Token {
 
 	switch l.char {
 	case '.':
+		if isDigit(l.peekChar()) { // fraction without integer part .5
+			tok = l.readNum()
+			return tok
+		}
 		tok = l.makeToken(token.DOT, ".")
 	case ':':
 		tok = l.makeToken(token.COLON, ":")
@@ -57,7 +65,16 @@ func (l *Lexer) NextToken() token.Token {
 	case '+':
 		tok = l.makeToken(token.PLUS, "+")
 	case '-':
-		tok = l.makeToken(token.MINUS, "-")
+		if l.peekChar() == '>' {
+			l.readChar()
+			tok = l.makeToken(token.ARROW, "->")
+		} else {
+			tok = l.makeToken(token.MINUS, "-")
+		}
+	case '|':
+		tok = l.makeToken(token.PIPE, "|")
+	case '&':
+		tok = l.makeToken(token.AMP, "&")
 	case '*':
 		tok = l.makeToken(token.STAR, "*")
 	case '/':
@@ -95,7 +112,7 @@ func (l *Lexer) NextToken() token.Token {
 	case 0:
 		tok = l.makeToken(token.EOF, "eof")
 	default:
-		if isAlpha(l.char) { // check [A-Za-z_]
+		if isLetter(l.char) { // check unicode letters
 			tok = l.readIdent()
 			return tok
 		} else if isDigit(l.char) { // check [0-9]
@@ -122,7 +139,7 @@ func (l *Lexer) makeToken(tokenType token.TokenType, word string) token.Token {
 		Word: word,
 	}
 
-	l.col += uint(len(word))
+	l.col += uint(utf8.RuneCountInString(word))
 	return token
 }
 
@@ -141,71 +158,137 @@ func (l *Lexer) makeErr(message string) token.Token {
 func (l *Lexer) readString() token.Token {
 	l.readChar() // consume '"'
 
-	start := l.offset - 1
+	start := l.pos
 
 	for l.char != '"' && l.char != 0 {
 		l.readChar()
 	}
 
-	word := l.input[start : l.offset-1]
+	word := l.input[start:l.pos]
 
-	return l.makeToken(token.STRING, word)
+	tok := l.makeToken(token.STRING, word)
+	l.col += 2 // surrounding quotes
+	return tok
 }
 
 func (l *Lexer) readIdent() token.Token {
 	var tokType token.TokenType
 
-	start := l.offset - 1
+	start := l.pos
 
-	for isAlpha(l.char) || isDigit(l.char) || l.char == '_' { // [A-Za-z_0-9]+
+	for isLetter(l.char) || unicode.IsDigit(l.char) || unicode.IsMark(l.char) || l.char == '_' {
 		l.readChar()
 	}
 
-	word := l.input[start : l.offset-1]
+	word := l.input[start:l.pos]
 	tokType = token.LookUpKeyword(word) // lookup for keywords: fn, let, return...
 
 	return l.makeToken(tokType, word)
 }
 
+// reads integer and float literals. integers can be decimal or have a
+// base prefix 0x, 0o or 0b, floats can have an exponent and digits can
+// be separated with '_' like 1_000_000. the spelling is kept as it is
+// and the value is parsed later by the parser
 func (l *Lexer) readNum() token.Token {
 	var tokType token.TokenType
 
-	start := l.offset - 1
+	start := l.pos
 
 	tokType = token.INT
-	for isDigit(l.char) { // [0-9]+
-		l.readChar()
-	}
+	if l.char == '0' && isBasePrefix(l.peekChar()) {
+		l.readChar() // consume '0'
+		base := l.char
+		l.readChar() // consume base prefix
 
-	if l.char == '.' { // floating point literal
-		l.readChar()
-		tokType = token.FLOAT
-		for isDigit(l.char) {
+		if l.readDigits(func(char rune) bool { return isBaseDigit(base, char) }) == 0 {
+			return l.numErr(start, "has no digits")
+		}
+	} else {
+		l.readDigits(isDigit) // [0-9_]*
+
+		if l.char == '.' { // floating point literal
 			l.readChar()
+			tokType = token.FLOAT
+			l.readDigits(isDigit)
+		}
+
+		if l.char == 'e' || l.char == 'E' { // exponent
+			l.readChar()
+			tokType = token.FLOAT
+			if l.char == '+' || l.char == '-' {
+				l.readChar()
+			}
+			if l.readDigits(isDigit) == 0 {
+				return l.numErr(start, "has no exponent digits")
+			}
 		}
 	}
 
-	word := l.input[start : l.offset-1]
+	// literal followed by letters or digits not valid for its base
+	if isLetter(l.char) || isDigit(l.char) {
+		invalid := l.char
+		return l.numErr(start, fmt.Sprintf("has invalid digit %q", invalid))
+	}
+
+	word := l.input[start:l.pos]
+	if !validSeparators(word) {
+		return l.numErr(start, "'_' must separate successive digits")
+	}
 
 	return l.makeToken(tokType, word)
 }
 
+// reads digits accepted by `isValid` along with '_' separators
+// and returns the number of digits read
+func (l *Lexer) readDigits(isValid func(rune) bool) int {
+	count := 0
+	for isValid(l.char) || l.char == '_' {
+		if l.char != '_' {
+			count++
+		}
+		l.readChar()
+	}
+
+	return count
+}
+
+// reports a malformed number literal, the rest of
+// the literal is skipped so lexing can continue after it
+func (l *Lexer) numErr(start uint, reason string) token.Token {
+	for isLetter(l.char) || isDigit(l.char) || l.char == '_' || l.char == '.' {
+		l.readChar()
+	}
+
+	word := l.input[start:l.pos]
+	tok := l.makeErr(fmt.Sprintf("number literal %q %s", word, reason))
+	l.col += uint(utf8.RuneCountInString(word))
+
+	return tok
+}
+
+// decodes the next UTF-8 character, invalid
+// bytes are read as utf8.RuneError
 func (l *Lexer) readChar() {
 	if l.offset >= uint(len(l.input)) {
 		l.char = 0
-	} else {
-		l.char = l.input[l.offset]
+		l.pos = uint(len(l.input))
+		return
 	}
 
-	l.offset++
+	char, width := utf8.DecodeRuneInString(l.input[l.offset:])
+	l.char = char
+	l.pos = l.offset
+	l.offset += uint(width)
 }
 
-func (l *Lexer) peekChar() byte {
-	if l.offset == uint(len(l.input)) {
+func (l *Lexer) peekChar() rune {
+	if l.offset >= uint(len(l.input)) {
 		return 0
 	}
 
-	return l.input[l.offset]
+	char, _ := utf8.DecodeRuneInString(l.input[l.offset:])
+	return char
 }
 
 func (l *Lexer) skipWhiteSpace() {
@@ -227,10 +310,56 @@ func (l *Lexer) skipWhiteSpace() {
 	}
 }
 
-func isAlpha(char byte) bool {
-	return 'a' <= char && char <= 'z' || 'A' <= char && char <= 'Z'
+func isLetter(char rune) bool {
+	return unicode.IsLetter(char)
 }
 
-func isDigit(char byte) bool {
+// number literals only use ASCII digits
+func isDigit(char rune) bool {
 	return '0' <= char && char <= '9'
 }
+
+func isBasePrefix(char rune) bool {
+	switch char {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return true
+	default:
+		return false
+	}
+}
+
+func isBaseDigit(base rune, char rune) bool {
+	switch base {
+	case 'x', 'X':
+		return isDigit(char) || 'a' <= char && char <= 'f' || 'A' <= char && char <= 'F'
+	case 'o', 'O':
+		return '0' <= char && char <= '7'
+	case 'b', 'B':
+		return char == '0' || char == '1'
+	default:
+		return isDigit(char)
+	}
+}
+
+// checks that every '_' in a number literal sits between two
+// digits, or directly after a base prefix like 0x_FF
+func validSeparators(word string) bool {
+	body := word
+	isValid := isDigit
+	if len(word) > 2 && word[0] == '0' && isBasePrefix(rune(word[1])) {
+		base := rune(word[1])
+		isValid = func(char rune) bool { return isBaseDigit(base, char) }
+		body = strings.TrimPrefix(word[2:], "_")
+	}
+
+	for i := 0; i < len(body); i++ {
+		if body[i] != '_' {
+			continue
+		}
+		if i == 0 || i == len(body)-1 || !isValid(rune(body[i-1])) || !isValid(rune(body[i+1])) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -37,6 +37,8 @@ a.b
 null
 break
 continue
+fn(x: int | null) -> int
+a | b & c in d
 `
 
 	tests := []struct {
@@ -138,6 +140,23 @@ continue
 		{token.NULL, "null"},
 		{token.BREAK, "break"},
 		{token.CONT, "continue"},
+		{token.FN, "fn"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.COLON, ":"},
+		{token.IDENT, "int"},
+		{token.PIPE, "|"},
+		{token.NULL, "null"},
+		{token.RPAREN, ")"},
+		{token.ARROW, "->"},
+		{token.IDENT, "int"},
+		{token.IDENT, "a"},
+		{token.PIPE, "|"},
+		{token.IDENT, "b"},
+		{token.AMP, "&"},
+		{token.IDENT, "c"},
+		{token.IN, "in"},
+		{token.IDENT, "d"},
 		{token.EOF, "eof"},
 	}
 
@@ -157,3 +176,80 @@ continue
 		}
 	}
 }
+
+func TestNumberLiterals(t *testing.T) {
+	input := `0xFF 0o755 0b1010 1_000_000 1e-9 .5 1.5E+3 0x_ff 12.`
+
+	tests := []struct {
+		expectType token.TokenType
+		expectWord string
+	}{
+		{token.INT, "0xFF"},
+		{token.INT, "0o755"},
+		{token.INT, "0b1010"},
+		{token.INT, "1_000_000"},
+		{token.FLOAT, "1e-9"},
+		{token.FLOAT, ".5"},
+		{token.FLOAT, "1.5E+3"},
+		{token.INT, "0x_ff"},
+		{token.FLOAT, "12."},
+		{token.EOF, "eof"},
+	}
+
+	lexer := New("lexer_test", input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectType || tok.Word != test.expectWord {
+			t.Fatalf("Test[%d] - wrong token. expect=%d[%q], found=%d[%q]",
+				i, test.expectType, test.expectWord, tok.Type, tok.Word)
+		}
+	}
+
+	malformed := []string{"0x", "0b12", "1__0", "1_", "1e", "0o8", "12abc"}
+	for i, input := range malformed {
+		tok := New("lexer_test", input).NextToken()
+		if tok.Type != token.ERR {
+			t.Errorf("Test[%d] - expected error for %q, found=%d[%q]", i, input, tok.Type, tok.Word)
+		}
+	}
+}
+
+func TestUnicode(t *testing.T) {
+	input := "let café = \"naïve ✓\";\nlet 名前 = café;"
+
+	tests := []struct {
+		expectType token.TokenType
+		expectWord string
+		expectCol  uint
+	}{
+		{token.LET, "let", 1},
+		{token.IDENT, "café", 5},
+		{token.ASSIGN, "=", 10},
+		{token.STRING, "naïve ✓", 12},
+		{token.SEMCOL, ";", 21},
+		{token.LET, "let", 1},
+		{token.IDENT, "名前", 5},
+		{token.ASSIGN, "=", 8},
+		{token.IDENT, "café", 10},
+		{token.SEMCOL, ";", 14},
+		{token.EOF, "eof", 15},
+	}
+
+	lexer := New("lexer_test", input)
+
+	for i, test := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != test.expectType || tok.Word != test.expectWord {
+			t.Fatalf("Test[%d] - wrong token. expect=%d[%q], found=%d[%q]",
+				i, test.expectType, test.expectWord, tok.Type, tok.Word)
+		}
+
+		if tok.Loc.Col != test.expectCol {
+			t.Fatalf("Test[%d] - wrong column for %q. expect=%d, found=%d",
+				i, tok.Word, test.expectCol, tok.Loc.Col)
+		}
+	}
+}
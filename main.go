@@ -2,35 +2,67 @@ package main
 
 import (
 	"RoLang/driver"
+	"RoLang/evaluator"
 	"RoLang/repl"
 
+	"flag"
 	"fmt"
 	"io"
 	"os"
 )
 
-const usage = `Usage: RoLang [FILE]
+const usage = `Usage: RoLang [-seed N] [FILE [ARGS...]]
+       RoLang check FILE
 	If FILE is absent starts the RoLang interpreter.
-	Otherwise interpretes the FILE.`
+	Otherwise interpretes the FILE, ARGS are available as os.args.
+	check type checks the FILE without running it.
+	-seed seeds the random module so that runs are reproducible.`
 
 func main() {
-	if len(os.Args) == 1 {
-		repl.Start()
-	} else if len(os.Args) == 2 {
-		file, err := os.Open(os.Args[1])
-		if err != nil {
-			fmt.Fprintln(os.Stdout, err)
-			os.Exit(1)
+	flags := flag.NewFlagSet("RoLang", flag.ExitOnError)
+	flags.Usage = func() { fmt.Println(usage) }
+	seed := flags.Int64("seed", 0, "")
+	flags.Parse(os.Args[1:])
+
+	var opts []evaluator.Option
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts = append(opts, evaluator.WithSeed(*seed))
 		}
+	})
 
-		bytes, err := io.ReadAll(file)
-		if err != nil {
-			fmt.Fprintln(os.Stdout, err)
+	args := flags.Args()
+	if len(args) == 0 {
+		os.Exit(repl.Start(opts...))
+	} else if args[0] == "check" {
+		if len(args) != 2 {
+			flags.Usage()
+			os.Exit(2)
+		}
+		name, code := readFile(args[1])
+		if !driver.Check(name, code) {
 			os.Exit(1)
 		}
-
-		driver.Execute(file.Name(), string(bytes))
 	} else {
-		fmt.Println(usage)
+		name, code := readFile(args[0])
+		opts = append(opts, evaluator.WithArgs(args[1:]...))
+		os.Exit(driver.Execute(name, code, opts...))
+	}
+}
+
+func readFile(path string) (string, string) {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stdout, err)
+		os.Exit(1)
 	}
+	defer file.Close()
+
+	bytes, err := io.ReadAll(file)
+	if err != nil {
+		fmt.Fprintln(os.Stdout, err)
+		os.Exit(1)
+	}
+
+	return file.Name(), string(bytes)
 }
@@ -5,14 +5,20 @@ import (
 	"RoLang/lexer"
 	"RoLang/token"
 
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 type Parser struct {
 	lexer *lexer.Lexer
 	// all error messages generated while parsing
 	errors []error
+	// locations of reported errors, only one error is kept per location
+	errorLocs map[token.SrcLoc]bool
+	// set when the statement being parsed reported an error
+	failed bool
 	// pointers for reading tokens
 	currToken token.Token
 	nextToken token.Token
@@ -33,21 +39,28 @@ type Entry struct {
 }
 
 const (
-	NONE    Precedence = iota
-	ASSIGN             // =
-	EQUALS             // == !=
-	COMPARE            // < > <= >=
-	SUM                // + -
-	PRODUCT            // * /
-	PREFIX             // !x -x
-	POSTFIX            // x() x++ x[1]
-	DOT                // a.b
+	NONE      Precedence = iota
+	ASSIGN               // =
+	EQUALS               // == !=
+	COMPARE              // < > <= >= in
+	UNION                // |
+	INTERSECT            // &
+	SUM                  // + -
+	PRODUCT              // * /
+	PREFIX               // !x -x
+	POSTFIX              // x() x++ x[1]
+	DOT                  // a.b
 )
 
+// parsing stops after this many errors since later
+// errors are mostly consequences of the earlier ones
+const maxErrors = 20
+
 func New(lexer *lexer.Lexer) *Parser {
 	p := &Parser{
-		lexer:  lexer,
-		errors: []error{},
+		lexer:     lexer,
+		errors:    []error{},
+		errorLocs: make(map[token.SrcLoc]bool),
 	}
 
 	// TODO put this into a global variable so that every time a parser
@@ -78,6 +91,9 @@ func New(lexer *lexer.Lexer) *Parser {
 		token.LE:     {nil, p.parseInfixExpression, COMPARE},
 		token.GT:     {nil, p.parseInfixExpression, COMPARE},
 		token.GE:     {nil, p.parseInfixExpression, COMPARE},
+		token.IN:     {nil, p.parseInfixExpression, COMPARE},
+		token.PIPE:   {nil, p.parseInfixExpression, UNION},
+		token.AMP:    {nil, p.parseInfixExpression, INTERSECT},
 		token.DOT:    {nil, p.parseInfixExpression, DOT},
 	}
 
@@ -93,9 +109,10 @@ func (p *Parser) Parse() (*ast.Program, []error) {
 	program.Statements = []ast.Statement{}
 
 	// Read until end of file
-	for !p.hasToken(token.EOF) {
-		stmt := p.ParseStatement()
-		program.Statements = append(program.Statements, stmt)
+	for !p.hasToken(token.EOF) && !p.tooManyErrors() {
+		if stmt, ok := p.parseStatementOrSync(); ok {
+			program.Statements = append(program.Statements, stmt)
+		}
 		// Set parser on the first token of next statement
 		p.readToken()
 	}
@@ -103,6 +120,37 @@ func (p *Parser) Parse() (*ast.Program, []error) {
 	return program, p.errors
 }
 
+// parses a statement and reports whether it parsed without errors. on an
+// error the parser skips ahead to the end of the broken statement so that
+// parsing can resume at the next one instead of cascading more errors
+func (p *Parser) parseStatementOrSync() (ast.Statement, bool) {
+	// a nested statement failing also fails the enclosing one
+	outer := p.failed
+	p.failed = false
+	defer func() { p.failed = p.failed || outer }()
+
+	stmt := p.ParseStatement()
+	if p.failed {
+		p.synchronize()
+		return nil, false
+	}
+
+	return stmt, true
+}
+
+// skips tokens until the current token ends a statement, that is a
+// ';' or the token before a '}' or a keyword that starts a statement
+func (p *Parser) synchronize() {
+	for !p.hasToken(token.EOF) && !p.hasToken(token.SEMCOL) {
+		switch p.nextToken.Type {
+		case token.RBRACE, token.EOF, token.LET, token.FN, token.RETURN,
+			token.IF, token.LOOP, token.BREAK, token.CONT:
+			return
+		}
+		p.readToken()
+	}
+}
+
 func (p *Parser) ParseStatement() ast.Statement {
 	switch p.currToken.Type {
 	case token.LET:
@@ -131,6 +179,12 @@ func (p *Parser) ParseStatement() ast.Statement {
 }
 
 func (p *Parser) ParseExpression(precedence Precedence) ast.Expression {
+	if p.hasToken(token.ERR) {
+		// the lexer stores its error message in the token
+		p.reportAt(p.currToken.Loc, p.currToken.Word)
+		return nil
+	}
+
 	prefix := p.table[p.currToken.Type].prefix
 	if prefix == nil {
 		p.noPrefixFuncError(p.currToken.Type)
@@ -174,11 +228,16 @@ func (p *Parser) parseFunctionStatement() *ast.FunctionStatement {
 		return nil
 	}
 
-	parameters := p.parseFunctionParameters()
+	parameters, paramTypes := p.parseFunctionParameters()
 	if parameters == nil {
 		return nil
 	}
 
+	returnType, ok := p.parseReturnType()
+	if !ok {
+		return nil
+	}
+
 	// assertive check for '{'
 	if !p.expectToken(token.LBRACE) {
 		return nil
@@ -192,6 +251,8 @@ func (p *Parser) parseFunctionStatement() *ast.FunctionStatement {
 	stmt.Value = &ast.FunctionLiteral{
 		Token:      stmt.Token,
 		Parameters: parameters,
+		ParamTypes: paramTypes,
+		ReturnType: returnType,
 		Body:       body,
 	}
 
@@ -249,6 +310,16 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		Value: p.currToken.Word,
 	}
 
+	// optional type annotation
+	if p.matchToken(token.COLON) {
+		p.readToken() // consume ':'
+		typ := p.parseType()
+		if typ == nil {
+			return nil
+		}
+		stmt.Type = typ
+	}
+
 	// match and consume an equals
 	if !p.expectToken(token.ASSIGN) {
 		return nil
@@ -312,7 +383,7 @@ func (p *Parser) parseIfStatement() ast.Statement {
 
 			stmt.Else = elze
 		} else {
-			p.report(fmt.Sprintf("expected 'if' or '{'. found %q", p.nextToken.Word))
+			p.report(fmt.Sprintf("expected \"if\" or \"{\", found %q", p.nextToken.Word))
 			return nil
 		}
 	}
@@ -350,17 +421,22 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	p.readToken()
 
 	for !p.hasToken(token.RBRACE) && !p.hasToken(token.EOF) {
-		stmt := p.ParseStatement()
-		if stmt == nil {
+		if p.tooManyErrors() {
 			return nil
 		}
-		block.Statements = append(block.Statements, stmt)
+
+		// broken statements are skipped so the rest of the block is still
+		// parsed, the enclosing statement fails but it can continue parsing
+		// past the end of this block
+		if stmt, ok := p.parseStatementOrSync(); ok {
+			block.Statements = append(block.Statements, stmt)
+		}
 
 		p.readToken() // read next statement's token
 	}
 
 	if p.hasToken(token.EOF) {
-		p.report("expect '}' at end of block reached end of file")
+		p.reportAt(p.currToken.Loc, `expected "}" at end of block, found end of file`)
 		return nil
 	}
 
@@ -529,12 +605,19 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	parameters := p.parseFunctionParameters()
+	parameters, paramTypes := p.parseFunctionParameters()
 	if parameters == nil {
 		return nil
 	}
 
 	fn.Parameters = parameters
+	fn.ParamTypes = paramTypes
+
+	returnType, ok := p.parseReturnType()
+	if !ok {
+		return nil
+	}
+	fn.ReturnType = returnType
 
 	if !p.expectToken(token.LBRACE) {
 		return nil
@@ -549,8 +632,9 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return fn
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+func (p *Parser) parseFunctionParameters() ([]*ast.Identifier, []ast.TypeExpr) {
 	idents := []*ast.Identifier{}
+	types := []ast.TypeExpr{}
 
 	for {
 		if p.peekToken(token.RPAREN) {
@@ -558,12 +642,23 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 		}
 
 		if !p.expectToken(token.IDENT) {
-			return nil
+			return nil, nil
 		}
 
 		ident := &ast.Identifier{Token: p.currToken, Value: p.currToken.Word}
 		idents = append(idents, ident)
 
+		// optional type annotation, nil when absent
+		var typ ast.TypeExpr
+		if p.matchToken(token.COLON) {
+			p.readToken() // consume ':'
+			typ = p.parseType()
+			if typ == nil {
+				return nil, nil
+			}
+		}
+		types = append(types, typ)
+
 		if !p.peekToken(token.COMMA) {
 			break
 		}
@@ -571,10 +666,153 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 	}
 
 	if !p.expectToken(token.RPAREN) {
+		return nil, nil
+	}
+
+	return idents, types
+}
+
+// parses an optional `-> type` after a parameter list. the
+// boolean result is false only if an annotation was malformed
+func (p *Parser) parseReturnType() (ast.TypeExpr, bool) {
+	if !p.matchToken(token.ARROW) {
+		return nil, true
+	}
+	p.readToken() // consume '->'
+
+	typ := p.parseType()
+	if typ == nil {
+		return nil, false
+	}
+
+	return typ, true
+}
+
+// type names that can appear in annotations
+var namedTypes = map[string]bool{
+	"int":      true,
+	"float":    true,
+	"string":   true,
+	"bool":     true,
+	"any":      true,
+	"function": true,
+	"set":      true,
+	"deque":    true,
+	"heap":     true,
+	"file":     true,
+	"regex":    true,
+}
+
+// parses a type annotation starting at the current token
+// union types bind loosest so `[int] | null` is a union
+func (p *Parser) parseType() ast.TypeExpr {
+	typ := p.parsePrimaryType()
+	if typ == nil {
 		return nil
 	}
 
-	return idents
+	if !p.peekToken(token.PIPE) {
+		return typ
+	}
+
+	union := &ast.UnionType{Token: p.nextToken, Types: []ast.TypeExpr{typ}}
+	for p.matchToken(token.PIPE) {
+		p.readToken() // consume '|'
+		typ := p.parsePrimaryType()
+		if typ == nil {
+			return nil
+		}
+		union.Types = append(union.Types, typ)
+	}
+
+	return union
+}
+
+func (p *Parser) parsePrimaryType() ast.TypeExpr {
+	switch p.currToken.Type {
+	case token.IDENT:
+		if !namedTypes[p.currToken.Word] {
+			p.reportAt(p.currToken.Loc, fmt.Sprintf("unknown type %q", p.currToken.Word))
+			return nil
+		}
+		return &ast.NamedType{Token: p.currToken, Name: p.currToken.Word}
+	case token.NULL:
+		return &ast.NamedType{Token: p.currToken, Name: "null"}
+	case token.LBRACK:
+		typ := &ast.ArrayType{Token: p.currToken}
+		p.readToken() // consume '['
+
+		elem := p.parseType()
+		if elem == nil {
+			return nil
+		}
+		typ.Elem = elem
+
+		if !p.expectToken(token.RBRACK) {
+			return nil
+		}
+
+		return typ
+	case token.LBRACE:
+		typ := &ast.MapType{Token: p.currToken}
+		p.readToken() // consume '{'
+
+		key := p.parseType()
+		if key == nil {
+			return nil
+		}
+		typ.Key = key
+
+		if !p.expectToken(token.COLON) {
+			return nil
+		}
+		p.readToken() // consume ':'
+
+		value := p.parseType()
+		if value == nil {
+			return nil
+		}
+		typ.Value = value
+
+		if !p.expectToken(token.RBRACE) {
+			return nil
+		}
+
+		return typ
+	case token.FN:
+		typ := &ast.FunctionType{Token: p.currToken, Params: []ast.TypeExpr{}}
+		if !p.expectToken(token.LPAREN) {
+			return nil
+		}
+
+		for !p.peekToken(token.RPAREN) {
+			p.readToken()
+			param := p.parseType()
+			if param == nil {
+				return nil
+			}
+			typ.Params = append(typ.Params, param)
+
+			if !p.matchToken(token.COMMA) {
+				break
+			}
+		}
+
+		if !p.expectToken(token.RPAREN) {
+			return nil
+		}
+
+		returnType, ok := p.parseReturnType()
+		if !ok {
+			return nil
+		}
+		typ.ReturnType = returnType
+
+		return typ
+	default:
+		p.report(fmt.Sprintf("expected a type, found %q", p.currToken.Word))
+		return nil
+	}
 }
 
 func (p *Parser) parseMapLiteral() ast.Expression {
@@ -666,14 +904,29 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	}
 }
 
+// the literal keeps its original spelling in Token.Word, so
+// 0xFF or 1_000 can be printed back the way they were written
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	l := &ast.IntegerLiteral{Token: p.currToken}
 
-	value, err := strconv.ParseInt(p.currToken.Word, 0, 64)
+	// the lexer has checked the digits and separators, only a prefix
+	// changes the base so a leading zero does not make a literal octal
+	word, base := p.currToken.Word, 10
+	if len(word) > 1 && word[0] == '0' && strings.ContainsRune("xXoObB", rune(word[1])) {
+		base = 0
+	} else {
+		word = strings.ReplaceAll(word, "_", "")
+	}
+
+	value, err := strconv.ParseInt(word, base, 64)
 	if err != nil {
-		err = fmt.Errorf("could not parse %q as integer. %s",
-			p.currToken.Word, err)
-		p.errors = append(p.errors, err)
+		if errors.Is(err, strconv.ErrRange) {
+			p.reportAt(p.currToken.Loc, fmt.Sprintf("integer literal %q overflows int64",
+				p.currToken.Word))
+		} else {
+			p.reportAt(p.currToken.Loc, fmt.Sprintf("could not parse %q as integer",
+				p.currToken.Word))
+		}
 		return nil
 	}
 
@@ -687,9 +940,13 @@ func (p *Parser) parseFloatLiteral() ast.Expression {
 
 	value, err := strconv.ParseFloat(p.currToken.Word, 64)
 	if err != nil {
-		err = fmt.Errorf("could not parse %q as float. %s",
-			p.currToken.Word, err)
-		p.errors = append(p.errors, err)
+		if errors.Is(err, strconv.ErrRange) {
+			p.reportAt(p.currToken.Loc, fmt.Sprintf("float literal %q overflows float64",
+				p.currToken.Word))
+		} else {
+			p.reportAt(p.currToken.Loc, fmt.Sprintf("could not parse %q as float",
+				p.currToken.Word))
+		}
 		return nil
 	}
 
@@ -743,16 +1000,37 @@ func (p *Parser) readToken() {
 }
 
 func (p *Parser) peekError(tokenType token.TokenType) {
-	p.report(fmt.Sprintf("expected next token to be %q, got %q instead",
+	p.report(fmt.Sprintf("expected %q, found %q",
 		token.TokenString[tokenType], p.nextToken.Word))
 }
 
 func (p *Parser) noPrefixFuncError(tokenType token.TokenType) {
-	p.report(fmt.Sprintf("no prefix parse function for %q found",
-		token.TokenString[tokenType]))
+	p.reportAt(p.currToken.Loc, fmt.Sprintf("expected an expression, found %q",
+		p.currToken.Word))
 }
 
 func (p *Parser) report(message string) {
-	err := fmt.Errorf("%s %s", p.nextToken.Loc, message)
+	p.reportAt(p.nextToken.Loc, message)
+}
+
+// records an error, errors at a location that already has
+// one are dropped as they are caused by the first error
+func (p *Parser) reportAt(loc token.SrcLoc, message string) {
+	p.failed = true
+
+	if p.errorLocs[loc] || p.tooManyErrors() {
+		return
+	}
+	p.errorLocs[loc] = true
+
+	if len(p.errors) == maxErrors {
+		message = "too many errors"
+	}
+
+	err := fmt.Errorf("%s %s", loc, message)
 	p.errors = append(p.errors, err)
 }
+
+func (p *Parser) tooManyErrors() bool {
+	return len(p.errors) > maxErrors
+}
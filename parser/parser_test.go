@@ -7,6 +7,7 @@ import (
 
 	"errors"
 	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -206,6 +207,49 @@ func TestFunctionStatement(t *testing.T) {
 	}
 }
 
+func TestTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"let x: int = 5;", "let x: int = 5;"},
+		{"let x: int | null = null;", "let x: int | null = null;"},
+		{"let x: [int | string] = [];", "let x: [int | string] = [];"},
+		{"let x: {string: [float]} = {};", "let x: {string: [float]} = {};"},
+		{"let f: fn(int, int) -> int = g;", "let f: fn(int, int) -> int = g;"},
+		{"fn add(x: int, y) -> int { }", "fn add(x: int, y) -> int {  }"},
+		{"let f = fn (x: any) -> function { };", "let f = fn (x: any) -> function {  };"},
+		{"let s: set | deque | heap = null;", "let s: set | deque | heap = null;"},
+		{"fn f(x: file, y: regex) { }", "fn f(x: file, y: regex) {  }"},
+	}
+
+	for i, test := range tests {
+		l := lexer.New("parser_test_types", test.input)
+		p := New(l)
+
+		program, errs := p.Parse()
+		checkErrors(t, errs)
+
+		if str := program.String(); str != test.expect {
+			t.Errorf("test[%d] program.String() wrong. got=%q, expect=%q",
+				i, str, test.expect)
+		}
+	}
+
+	l := lexer.New("parser_test_types", "let x: integer = 5;")
+	p := New(l)
+
+	_, errs := p.Parse()
+	if len(errs) == 0 {
+		t.Fatalf("expected an error for unknown type")
+	}
+	// the error points at the type, not at the token after it
+	expect := `parser_test_types:1:8: unknown type "integer"`
+	if errs[0].Error() != expect {
+		t.Errorf("wrong error. got=%q, expect=%q", errs[0], expect)
+	}
+}
+
 func TestReturnStatement(t *testing.T) {
 	input := `
 return 5;
@@ -894,6 +938,78 @@ func TestFloatLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestNumberLiteralSpellings(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect any
+	}{
+		{"0xFF;", 255},
+		{"0o755;", 493},
+		{"0b1010;", 10},
+		{"1_000_000;", 1000000},
+		{"010;", 10},
+		{"09;", 9},
+		{"0_7;", 7},
+		{"0x_1F;", 31},
+		{"1e-9;", 1e-9},
+		{".5;", 0.5},
+		{"1_000.5e1;", 10005.0},
+	}
+
+	for i, test := range tests {
+		l := lexer.New("parser_test_num", test.input)
+		p := New(l)
+
+		program, errs := p.Parse()
+		checkErrors(t, errs)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		switch expect := test.expect.(type) {
+		case int:
+			if !testIntLiteral(t, stmt.Expression, int64(expect)) {
+				t.Logf("test[%d]\n", i)
+			}
+		case float64:
+			if !testFloatLiteral(t, stmt.Expression, expect) {
+				t.Logf("test[%d]\n", i)
+			}
+		}
+
+		// original spelling is preserved
+		if str := program.String(); str != strings.TrimSuffix(test.input, ";") {
+			t.Errorf("test[%d] wrong spelling. got=%q, expect=%q", i, str, test.input)
+		}
+	}
+}
+
+func TestNumberLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"9223372036854775808;", `parser_test_num:1:1: integer literal "9223372036854775808" overflows int64`},
+		{"let x = 1e999;", `parser_test_num:1:9: float literal "1e999" overflows float64`},
+		{"let x = 0x;", `parser_test_num:1:9: number literal "0x" has no digits`},
+		{"let x = 0b102;", `parser_test_num:1:9: number literal "0b102" has invalid digit '2'`},
+		{"let x = 1__0;", `parser_test_num:1:9: number literal "1__0" '_' must separate successive digits`},
+	}
+
+	for i, test := range tests {
+		l := lexer.New("parser_test_num", test.input)
+		p := New(l)
+
+		_, errs := p.Parse()
+		if len(errs) == 0 {
+			t.Errorf("test[%d] expected error %q", i, test.expect)
+			continue
+		}
+
+		if errs[0].Error() != test.expect {
+			t.Errorf("test[%d] wrong error. got=%q, expect=%q", i, errs[0], test.expect)
+		}
+	}
+}
+
 func TestStringLiteralExpression(t *testing.T) {
 	input := `"hello world";`
 	expectStr := "hello world"
@@ -1023,6 +1139,14 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"x.y == a.b",
 			"((x . y) == (a . b))",
 		},
+		{
+			"a | b & c - d",
+			"(a | (b & (c - d)))",
+		},
+		{
+			"x in a | b == !y",
+			"((x in (a | b)) == (!y))",
+		},
 	}
 
 	for _, test := range tests {
@@ -1038,6 +1162,76 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 	}
 }
 
+func TestErrorRecovery(t *testing.T) {
+	input := `
+let x = ;
+let y = 1 +;
+fn f(a, b) {
+	let z = a +* b;
+	return z
+}
+let ok = 2;
+let w = [1, 2;
+`
+	expects := []string{
+		`parser_test_recovery:2:9: expected an expression, found ";"`,
+		`parser_test_recovery:3:12: expected an expression, found ";"`,
+		`parser_test_recovery:5:16: expected an expression, found "*"`,
+		`parser_test_recovery:7:1: expected ";", found "}"`,
+		`parser_test_recovery:9:14: expected "]", found ";"`,
+	}
+
+	l := lexer.New("parser_test_recovery", input)
+	p := New(l)
+
+	program, errs := p.Parse()
+	if len(errs) != len(expects) {
+		t.Fatalf("wrong number of errors. got=%d, expect=%d\n%v",
+			len(errs), len(expects), errors.Join(errs...))
+	}
+
+	for i, err := range errs {
+		if err.Error() != expects[i] {
+			t.Errorf("errs[%d] wrong. got=%q, expect=%q", i, err, expects[i])
+		}
+	}
+
+	// only the statement that parsed without errors is kept
+	if n := len(program.Statements); n != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", n)
+	}
+
+	if !testIdentifier(t, program.Statements[0].(*ast.LetStatement).Ident, "ok") {
+		return
+	}
+
+	// a block left open is reported at the end of the file
+	l = lexer.New("parser_test_recovery", "fn f() {\n\tlet a = 1;\n")
+	p = New(l)
+
+	_, errs = p.Parse()
+	expect := `parser_test_recovery:3:1: expected "}" at end of block, found end of file`
+	if len(errs) == 0 || errs[0].Error() != expect {
+		t.Errorf("wrong errors. got=%v, expect=%q", errs, expect)
+	}
+}
+
+func TestErrorLimit(t *testing.T) {
+	input := strings.Repeat("let = 1;\n", 50)
+
+	l := lexer.New("parser_test_limit", input)
+	p := New(l)
+
+	_, errs := p.Parse()
+	if len(errs) != maxErrors+1 {
+		t.Fatalf("wrong number of errors. got=%d, expect=%d", len(errs), maxErrors+1)
+	}
+
+	if last := errs[len(errs)-1].Error(); !strings.HasSuffix(last, "too many errors") {
+		t.Errorf("last error wrong. got=%q", last)
+	}
+}
+
 func TestString(t *testing.T) {
 	program := &ast.Program{
 		Statements: []ast.Statement{
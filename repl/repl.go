@@ -24,16 +24,32 @@ func checkError(errs []error) bool {
 	return false
 }
 
-func Start() {
+// confirms whether the session should end after
+// a top level return asked for the given exit code
+func confirmExit(scanner *bufio.Scanner, code int64) bool {
+	fmt.Printf("script requested exit %d\n", code)
+	fmt.Print("exit the repl? [y/N] ")
+
+	if !scanner.Scan() {
+		return true
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return answer == "y" || answer == "yes"
+}
+
+// Start runs the interactive session and returns the exit code
+// the process should end with once the session is over
+func Start(opts ...evaluator.Option) int {
 	fmt.Println(message)
 	scanner := bufio.NewScanner(os.Stdin)
-	e := evaluator.New()
+	e := evaluator.New(opts...)
 	for {
 		fmt.Print(prompt)
 
 		scanned := scanner.Scan()
 		if !scanned {
-			return
+			return 0
 		}
 
 		line := strings.TrimSpace(scanner.Text())
@@ -50,9 +66,13 @@ func Start() {
 			continue
 		}
 
-		errs = e.Evaluate(program)
+		exit, errs := e.Evaluate(program)
 		if checkError(errs) {
 			continue
 		}
+
+		if exit != nil && confirmExit(scanner, exit.Code) {
+			return int(exit.Code)
+		}
 	}
 }
@@ -0,0 +1,368 @@
+package rolang
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/common"
+
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+var errCyclic = errors.New("cyclic value")
+
+// a pointer, map or slice being converted, values which
+// contain themselves are found by meeting one again
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// ToValue converts a Go value into a RoLang value.
+//
+// Integers become int64 (or a big integer if they do not fit), floats
+// become float64, slices and arrays become arrays and maps with int, float,
+// string or bool keys become maps. Structs become maps of their exported
+// fields, a `rolang:"name"` tag renames a field and `rolang:"-"` skips it.
+// Pointers and interfaces are followed and functions of type
+// func(...any) (any, error) can be called from RoLang.
+// Values that already are RoLang values are returned unchanged.
+// Values which contain themselves cannot be converted.
+func ToValue(v any) (Value, error) {
+	return fromGo(v, map[visit]bool{})
+}
+
+func fromGo(v any, visiting map[visit]bool) (Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case int64, float64, string, bool,
+		*objects.ArrayObject, *objects.MapObject,
+		*objects.SetObject, *objects.DequeObject, *objects.HeapObject,
+		*objects.RegexObject, objects.FuncObject, common.Sanitizer:
+		return v, nil
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		return objects.NormalizeBig(new(big.Int).Set(v)), nil
+	case func(...any) (any, error):
+		return common.Sanitizer(v), nil
+	}
+
+	return toValue(reflect.ValueOf(v), visiting)
+}
+
+// marks a pointer, map or slice as being converted, leave
+// removes the mark once its elements have been converted
+func enter(visiting map[visit]bool, v reflect.Value, length int) (leave func(), err error) {
+	key := visit{ptr: v.Pointer(), typ: v.Type(), len: length}
+	if visiting[key] {
+		return nil, errCyclic
+	}
+	visiting[key] = true
+
+	return func() { delete(visiting, key) }, nil
+}
+
+func toValue(v reflect.Value, visiting map[visit]bool) (Value, error) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type() == bigIntType {
+			return objects.NormalizeBig(new(big.Int).Set(v.Interface().(*big.Int))), nil
+		}
+		if v.Kind() == reflect.Pointer {
+			leave, err := enter(visiting, v, 0)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+		}
+		return fromGo(v.Elem().Interface(), visiting)
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			return new(big.Int).SetUint64(u), nil
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		if v.Kind() == reflect.Slice && v.Len() > 0 {
+			leave, err := enter(visiting, v, v.Len())
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+		}
+		list := make([]any, v.Len())
+		for i := range list {
+			elem, err := toValue(v.Index(i), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			list[i] = elem
+		}
+		return &objects.ArrayObject{List: list}, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		switch v.Type().Key().Kind() {
+		case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			return nil, fmt.Errorf("unsupported map key type: %s", v.Type().Key())
+		}
+		leave, err := enter(visiting, v, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		// go maps have no order, so the entries are sorted by key
+		type entry struct {
+			key   any
+			value reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := toValue(iter.Key(), visiting)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry{key, iter.Value()})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return objects.CompareKeys(a.key, b.key)
+		})
+
+		m := &objects.MapObject{}
+		for _, e := range entries {
+			val, err := toValue(e.value, visiting)
+			if err != nil {
+				return nil, fmt.Errorf("key %v: %w", e.key, err)
+			}
+			m.Set(e.key, val)
+		}
+		return m, nil
+	case reflect.Struct:
+		m := &objects.MapObject{}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, ok := fieldName(field)
+			if !ok {
+				continue
+			}
+			val, err := toValue(v.Field(i), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			m.Set(name, val)
+		}
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type: %s", v.Type())
+}
+
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag, _, _ := strings.Cut(field.Tag.Get("rolang"), ",")
+	switch tag {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return tag, true
+	}
+}
+
+// Decode stores a RoLang value into the Go value pointed to by out,
+// following the same rules as ToValue in reverse. Decoding into an
+// `any` stores the RoLang value as it is
+func Decode(value Value, out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer")
+	}
+
+	return decode(value, v.Elem())
+}
+
+func decode(value Value, out reflect.Value) error {
+	if key, ok := value.(objects.BigIntKey); ok {
+		value = key.Int()
+	}
+
+	if out.Kind() == reflect.Interface && out.NumMethod() == 0 {
+		if value == nil {
+			out.SetZero()
+		} else {
+			out.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+
+	if out.Type() == bigIntType {
+		switch v := value.(type) {
+		case int64:
+			out.Set(reflect.ValueOf(big.NewInt(v)))
+		case *big.Int:
+			out.Set(reflect.ValueOf(new(big.Int).Set(v)))
+		default:
+			return mismatch(value, out)
+		}
+		return nil
+	}
+
+	if value == nil {
+		switch out.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			out.SetZero()
+			return nil
+		}
+		return mismatch(value, out)
+	}
+
+	switch out.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(out.Type().Elem())
+		if err := decode(value, elem.Elem()); err != nil {
+			return err
+		}
+		out.Set(elem)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return mismatch(value, out)
+		}
+		out.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := value.(int64)
+		if !ok {
+			return mismatch(value, out)
+		}
+		if out.OverflowInt(i) {
+			return fmt.Errorf("%d overflows %s", i, out.Type())
+		}
+		out.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch v := value.(type) {
+		case int64:
+			if v < 0 {
+				return fmt.Errorf("%d overflows %s", v, out.Type())
+			}
+			u = uint64(v)
+		case *big.Int:
+			if !v.IsUint64() {
+				return fmt.Errorf("%s overflows %s", v, out.Type())
+			}
+			u = v.Uint64()
+		default:
+			return mismatch(value, out)
+		}
+		if out.OverflowUint(u) {
+			return fmt.Errorf("%d overflows %s", u, out.Type())
+		}
+		out.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		switch v := value.(type) {
+		case float64:
+			out.SetFloat(v)
+		case int64:
+			out.SetFloat(float64(v))
+		default:
+			return mismatch(value, out)
+		}
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return mismatch(value, out)
+		}
+		out.SetString(s)
+	case reflect.Slice, reflect.Array:
+		arr, ok := value.(*objects.ArrayObject)
+		if !ok {
+			return mismatch(value, out)
+		}
+		if out.Kind() == reflect.Slice {
+			out.Set(reflect.MakeSlice(out.Type(), len(arr.List), len(arr.List)))
+		} else if out.Len() != len(arr.List) {
+			return fmt.Errorf("cannot decode array of length %d into %s", len(arr.List), out.Type())
+		}
+		for i, elem := range arr.List {
+			if err := decode(elem, out.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+	case reflect.Map:
+		m, ok := value.(*objects.MapObject)
+		if !ok {
+			return mismatch(value, out)
+		}
+		out.Set(reflect.MakeMapWithSize(out.Type(), int(m.Len())))
+		for k, v := range m.All() {
+			key := reflect.New(out.Type().Key()).Elem()
+			if err := decode(k, key); err != nil {
+				return err
+			}
+			val := reflect.New(out.Type().Elem()).Elem()
+			if err := decode(v, val); err != nil {
+				return fmt.Errorf("key %v: %w", k, err)
+			}
+			out.SetMapIndex(key, val)
+		}
+	case reflect.Struct:
+		m, ok := value.(*objects.MapObject)
+		if !ok {
+			return mismatch(value, out)
+		}
+		for i := 0; i < out.NumField(); i++ {
+			name, ok := fieldName(out.Type().Field(i))
+			if !ok {
+				continue
+			}
+			if v, ok := m.Get(name); ok {
+				if err := decode(v, out.Field(i)); err != nil {
+					return fmt.Errorf("field %s: %w", name, err)
+				}
+			}
+		}
+	default:
+		return mismatch(value, out)
+	}
+
+	return nil
+}
+
+func mismatch(value Value, out reflect.Value) error {
+	return fmt.Errorf("cannot decode %T into %s", value, out.Type())
+}
@@ -0,0 +1,200 @@
+// Package rolang embeds the RoLang interpreter in Go programs.
+//
+//	in := rolang.NewInterpreter(rolang.Options{})
+//	in.Set("limit", 10)
+//	value, err := in.Eval(ctx, "limit * 2;")
+//
+// Values exchanged with the interpreter are the RoLang runtime values:
+// int64, *big.Int, float64, string, bool, nil, *objects.ArrayObject,
+// *objects.MapObject and functions. ToValue and Decode convert between
+// these and ordinary Go values.
+package rolang
+
+import (
+	"RoLang/evaluator"
+	"RoLang/lexer"
+	"RoLang/parser"
+	"RoLang/stdlib"
+	"RoLang/stdlib/common"
+
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Value is any RoLang runtime value
+type Value = any
+
+type Options struct {
+	// File is the name used in error locations, "eval" if empty
+	File string
+	// TypeChecks enables checking of type annotations at runtime
+	TypeChecks bool
+	// CheckedOverflow makes integer overflow an error instead of
+	// promoting the result to a big integer
+	CheckedOverflow bool
+	// MaxSteps limits the statements and expressions evaluated
+	// by a single call to Eval or Call, 0 means no limit
+	MaxSteps int64
+	// MaxCallDepth limits how deeply function calls may nest,
+	// 0 keeps the evaluator's default
+	MaxCallDepth int
+	// MaxElements caps the array and map elements allocated
+	// by a single call to Eval or Call, 0 means no limit
+	MaxElements int64
+	// Policy restricts the stdlib modules and functions scripts may use
+	Policy stdlib.Policy
+	// Seed makes the random module deterministic,
+	// a random seed is used when it is nil
+	Seed *int64
+	// Args are the command line arguments exposed as `os.args`
+	Args []string
+	// streams used by the io module, the process'
+	// standard streams are used for the ones left nil
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ExitError is returned by Eval when the script ends with a top level
+// return or os.exit, and by Call when the function calls os.exit, which
+// would exit the process when run from the command line
+type ExitError struct {
+	Code  int64
+	Value Value
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("script requested exit %d", e.Code)
+}
+
+// Interpreter keeps its global variables across calls to Eval.
+// It is not safe for concurrent use
+type Interpreter struct {
+	options   Options
+	evaluator *evaluator.Evaluator
+}
+
+func NewInterpreter(options Options) *Interpreter {
+	if options.File == "" {
+		options.File = "eval"
+	}
+
+	in := &Interpreter{options: options}
+
+	opts := []evaluator.Option{
+		evaluator.WithPolicy(options.Policy),
+		evaluator.WithArgs(options.Args...),
+		evaluator.WithStdin(options.Stdin),
+		evaluator.WithStdout(options.Stdout),
+		evaluator.WithStderr(options.Stderr),
+	}
+	if options.TypeChecks {
+		opts = append(opts, evaluator.WithTypeChecks())
+	}
+	if options.CheckedOverflow {
+		opts = append(opts, evaluator.WithCheckedOverflow())
+	}
+	if options.MaxSteps > 0 {
+		opts = append(opts, evaluator.WithMaxSteps(options.MaxSteps))
+	}
+	if options.MaxCallDepth > 0 {
+		opts = append(opts, evaluator.WithMaxCallDepth(options.MaxCallDepth))
+	}
+	if options.Seed != nil {
+		opts = append(opts, evaluator.WithSeed(*options.Seed))
+	}
+	if options.MaxElements > 0 {
+		opts = append(opts, evaluator.WithMaxElements(options.MaxElements))
+	}
+
+	in.evaluator = evaluator.New(opts...)
+
+	return in
+}
+
+// Eval runs the source code and returns the value of its last statement,
+// or nil when that is not an expression statement. Parse and runtime
+// errors are joined into the returned error.
+// Evaluation stops with an error once the context is done
+func (in *Interpreter) Eval(ctx context.Context, src string) (Value, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	l := lexer.New(in.options.File, src)
+	p := parser.New(l)
+
+	program, errs := p.Parse()
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	exit, errs := in.evaluator.EvaluateContext(ctx, program)
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if exit != nil {
+		return nil, &ExitError{Code: exit.Code, Value: exit.Value}
+	}
+
+	return in.evaluator.Result(), nil
+}
+
+// Set converts a Go value with ToValue and stores it in a
+// global variable, replacing any previous value
+func (in *Interpreter) Set(name string, value any) error {
+	v, err := ToValue(value)
+	if err != nil {
+		return fmt.Errorf("cannot set %s: %w", name, err)
+	}
+
+	in.evaluator.Define(name, v)
+	return nil
+}
+
+// Get returns the value of a global variable
+func (in *Interpreter) Get(name string) (Value, bool) {
+	return in.evaluator.Lookup(name)
+}
+
+// Call invokes a RoLang function, either a function value or the name
+// of a global variable holding one. Arguments are converted with ToValue
+func (in *Interpreter) Call(fn any, args ...any) (Value, error) {
+	return in.CallContext(context.Background(), fn, args...)
+}
+
+// CallContext is like Call but stops the function with
+// an error once the context is done
+func (in *Interpreter) CallContext(ctx context.Context, fn any, args ...any) (Value, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if name, ok := fn.(string); ok {
+		value, ok := in.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("variable not found: %s", name)
+		}
+		fn = value
+	}
+
+	values := make([]any, len(args))
+	for i, arg := range args {
+		v, err := ToValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		values[i] = v
+	}
+
+	value, err := in.evaluator.CallContext(ctx, fn, values...)
+	var exit common.Exit
+	if errors.As(err, &exit) {
+		return nil, &ExitError{Code: exit.Code, Value: exit.Code}
+	}
+
+	return value, err
+}
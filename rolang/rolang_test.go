@@ -0,0 +1,402 @@
+package rolang
+
+import (
+	"RoLang/evaluator"
+	"RoLang/evaluator/objects"
+	rostrings "RoLang/stdlib/strings"
+
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect Value
+	}{
+		{"1 + 2;", int64(3)},
+		{"let a = 5; a * 2.5;", 12.5},
+		{`"a" + "b";`, "ab"},
+		{"let a = 1;", nil},
+		{"fn f(x) { return x * 2; } f(21);", int64(42)},
+		{"fn f() { 1; return 2; } 5; let y = f();", nil},
+		{"fn f() { 1; return 2; } f();", int64(2)},
+		{"3; if true { 4; }", nil},
+	}
+
+	for i, test := range tests {
+		in := NewInterpreter(Options{})
+		value, err := in.Eval(context.Background(), test.input)
+		if err != nil {
+			t.Fatalf("test[%d]: unexpected error: %v", i, err)
+		}
+		if value != test.expect {
+			t.Errorf("test[%d]: expected %v (%T), got %v (%T)", i, test.expect, test.expect, value, value)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	in := NewInterpreter(Options{File: "embed"})
+
+	if _, err := in.Eval(context.Background(), "let = 5;"); err == nil {
+		t.Errorf("expected a parse error")
+	}
+
+	_, err := in.Eval(context.Background(), "1 / 0;")
+	if err == nil {
+		t.Fatalf("expected a runtime error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := in.Eval(ctx, "1;"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestEvalLimits(t *testing.T) {
+	in := NewInterpreter(Options{MaxSteps: 10_000})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := in.Eval(ctx, "loop { }")
+	if !errors.Is(err, evaluator.ErrStepLimit) && !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the loop to be stopped, got %v", err)
+	}
+
+	in = NewInterpreter(Options{})
+	_, err = in.Eval(ctx, "loop { }")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestEvalExit(t *testing.T) {
+	in := NewInterpreter(Options{})
+
+	_, err := in.Eval(context.Background(), "let a = 1; return 3; a = 2;")
+	var exit *ExitError
+	if !errors.As(err, &exit) {
+		t.Fatalf("expected an ExitError, got %v", err)
+	}
+	if exit.Code != 3 {
+		t.Errorf("expected exit code 3, got %d", exit.Code)
+	}
+
+	// the interpreter is still usable after the script exited
+	value, err := in.Eval(context.Background(), "a;")
+	if err != nil || value != int64(1) {
+		t.Errorf("expected 1, got %v (%v)", value, err)
+	}
+
+	in = NewInterpreter(Options{Args: []string{"5"}})
+	_, err = in.Eval(context.Background(), "fn quit() { os.exit(int(os.args[0])); } quit();")
+	if !errors.As(err, &exit) || exit.Code != 5 {
+		t.Errorf("expected an ExitError with code 5, got %v", err)
+	}
+
+	// functions called from Go end the script the same way
+	_, err = in.Call("quit")
+	if !errors.As(err, &exit) || exit.Code != 5 {
+		t.Errorf("expected an ExitError with code 5 from Call, got %v", err)
+	}
+}
+
+func TestGlobals(t *testing.T) {
+	in := NewInterpreter(Options{})
+
+	if err := in.Set("limit", 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := in.Eval(context.Background(), "let doubled = limit * 2; limit = 1;"); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, ok := in.Get("doubled"); !ok || value != int64(20) {
+		t.Errorf("expected doubled to be 20, got %v", value)
+	}
+	if value, ok := in.Get("limit"); !ok || value != int64(1) {
+		t.Errorf("expected limit to be 1, got %v", value)
+	}
+	if _, ok := in.Get("missing"); ok {
+		t.Errorf("expected missing to be undefined")
+	}
+}
+
+func TestCall(t *testing.T) {
+	in := NewInterpreter(Options{})
+
+	_, err := in.Eval(context.Background(), `
+		let counter = 0;
+		fn add(a, b) { counter = counter + 1; return a + b; }
+		let make = fn(n) { return fn(x) { return x * n; }; };
+		let triple = make(3);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := in.Call("add", 2, uint8(3))
+	if err != nil || value != int64(5) {
+		t.Errorf("expected 5, got %v (%v)", value, err)
+	}
+
+	triple, _ := in.Get("triple")
+	value, err = in.Call(triple, 1.5)
+	if err != nil || value != 4.5 {
+		t.Errorf("expected 4.5, got %v (%v)", value, err)
+	}
+
+	if value, _ := in.Get("counter"); value != int64(1) {
+		t.Errorf("expected counter to be 1, got %v", value)
+	}
+
+	if _, err := in.Call("missing"); err == nil {
+		t.Errorf("expected an error calling an undefined function")
+	}
+	if _, err := in.Call("add", 1, true); err == nil {
+		t.Errorf("expected a runtime error from the function")
+	}
+}
+
+func TestCallLimits(t *testing.T) {
+	in := NewInterpreter(Options{MaxSteps: 50})
+
+	_, err := in.Eval(context.Background(), `
+		fn id(x) { return x; }
+		fn spin() { loop { } }
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every call has its own budget
+	for i := range 100 {
+		if _, err := in.Call("id", i); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+	if _, err := in.Call("spin"); !errors.Is(err, evaluator.ErrStepLimit) {
+		t.Errorf("expected evaluator.ErrStepLimit, got %v", err)
+	}
+	if _, err := in.Call("id", 1); err != nil {
+		t.Errorf("unexpected error after a failed call: %v", err)
+	}
+
+	// go functions called by a script share the script's budget
+	err = in.Set("again", func(args ...any) (any, error) {
+		return in.Call(args[0], 1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := in.Eval(ctx, "loop { again(id); }"); !errors.Is(err, evaluator.ErrStepLimit) {
+		t.Errorf("expected evaluator.ErrStepLimit, got %v", err)
+	}
+
+	// runaway calls can be cancelled
+	in = NewInterpreter(Options{})
+	if _, err := in.Eval(context.Background(), "fn spin() { loop { } }"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := in.CallContext(ctx, "spin"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if _, err := in.CallContext(ctx, "spin"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded for a done context, got %v", err)
+	}
+}
+
+func TestGoFunction(t *testing.T) {
+	in := NewInterpreter(Options{})
+
+	err := in.Set("twice", func(args ...any) (any, error) {
+		return args[0].(int64) * 2, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := in.Eval(context.Background(), "twice(21);")
+	if err != nil || value != int64(42) {
+		t.Errorf("expected 42, got %v (%v)", value, err)
+	}
+}
+
+func TestStreams(t *testing.T) {
+	var stdout bytes.Buffer
+	in := NewInterpreter(Options{Stdin: strings.NewReader("ro\n"), Stdout: &stdout})
+
+	if _, err := in.Eval(context.Background(), `io.println("hi " + io.readln());`); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "hi ro\n" {
+		t.Errorf("expected %q, got %q", "hi ro\n", stdout.String())
+	}
+}
+
+func TestSeed(t *testing.T) {
+	script := `[random.int(1, 1000), random.float(), random.shuffle([1, 2, 3, 4, 5]), random.sample([1, 2, 3, 4, 5], 2)];`
+
+	run := func(seed int64) string {
+		in := NewInterpreter(Options{Seed: &seed})
+		value, err := in.Eval(context.Background(), script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rostrings.From(value)
+	}
+
+	if first, second := run(7), run(7); first != second {
+		t.Errorf("expected equal seeds to give equal values, got %s and %s", first, second)
+	}
+	if first, second := run(7), run(8); first == second {
+		t.Errorf("expected different seeds to give different values, got %s twice", first)
+	}
+
+	// reseeding from the script restarts the sequence
+	in := NewInterpreter(Options{})
+	value, err := in.Eval(context.Background(), `random.seed(3); let a = random.int(0, 1000000); random.seed(3); a == random.int(0, 1000000);`)
+	if err != nil || value != true {
+		t.Errorf("expected reseeding to repeat values, got %v (%v)", value, err)
+	}
+}
+
+type point struct {
+	X, Y   int
+	Label  string `rolang:"label"`
+	Hidden bool   `rolang:"-"`
+	secret int
+}
+
+func TestToValue(t *testing.T) {
+	value, err := ToValue(point{X: 1, Y: 2, Label: "p", Hidden: true, secret: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, ok := value.(*objects.MapObject)
+	if !ok {
+		t.Fatalf("expected a map, got %T", value)
+	}
+	expect := map[any]any{"X": int64(1), "Y": int64(2), "label": "p"}
+	if got := maps.Collect(m.All()); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+
+	value, err = ToValue([]*point{{X: 1}, nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr := value.(*objects.ArrayObject)
+	if len(arr.List) != 2 || arr.List[1] != nil {
+		t.Errorf("unexpected array %v", arr.List)
+	}
+
+	value, _ = ToValue(uint64(math.MaxUint64))
+	if b, ok := value.(*big.Int); !ok || b.String() != "18446744073709551615" {
+		t.Errorf("expected a big integer, got %v", value)
+	}
+
+	// go maps are converted with their keys sorted
+	value, _ = ToValue(map[string]int{"c": 1, "a": 2, "b": 3})
+	if keys := value.(*objects.MapObject).Keys(); !reflect.DeepEqual(keys, []any{"a", "b", "c"}) {
+		t.Errorf("expected sorted keys, got %v", keys)
+	}
+
+	// the value of a NaN key is kept although NaN is not equal to itself
+	value, _ = ToValue(map[float64]int{math.NaN(): 1})
+	if got := value.(*objects.MapObject).Values(); !reflect.DeepEqual(got, []any{int64(1)}) {
+		t.Errorf("expected the value of the NaN key, got %v", got)
+	}
+
+	if _, err := ToValue(map[[2]int]int{}); err == nil {
+		t.Errorf("expected an error for unsupported key type")
+	}
+	if _, err := ToValue(make(chan int)); err == nil {
+		t.Errorf("expected an error for unsupported type")
+	}
+}
+
+type node struct {
+	Next *node
+}
+
+func TestToValueCycles(t *testing.T) {
+	n := &node{}
+	n.Next = n
+	if _, err := ToValue(n); err == nil || !strings.Contains(err.Error(), "cyclic value") {
+		t.Errorf("expected a cyclic value error, got %v", err)
+	}
+
+	m := map[string]any{}
+	m["self"] = m
+	if _, err := ToValue(m); err == nil || !strings.Contains(err.Error(), "cyclic value") {
+		t.Errorf("expected a cyclic value error, got %v", err)
+	}
+
+	s := []any{nil}
+	s[0] = s
+	if _, err := ToValue(s); err == nil || !strings.Contains(err.Error(), "cyclic value") {
+		t.Errorf("expected a cyclic value error, got %v", err)
+	}
+
+	// values shared without a cycle are converted every time they appear
+	shared := &point{X: 1}
+	value, err := ToValue([]*point{shared, shared})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if arr := value.(*objects.ArrayObject); len(arr.List) != 2 || arr.List[1] == nil {
+		t.Errorf("unexpected array %v", arr.List)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	in := NewInterpreter(Options{})
+
+	value, err := in.Eval(context.Background(), `let m = {"X": 3, "Y": 4, "label": "q"}; m;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p point
+	if err := Decode(value, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.X != 3 || p.Y != 4 || p.Label != "q" {
+		t.Errorf("unexpected struct %+v", p)
+	}
+
+	value, _ = in.Eval(context.Background(), "[1, 2, 3];")
+	var list []float64
+	if err := Decode(value, &list); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(list, []float64{1, 2, 3}) {
+		t.Errorf("unexpected slice %v", list)
+	}
+
+	var small int8
+	if err := Decode(int64(300), &small); err == nil {
+		t.Errorf("expected an overflow error")
+	}
+	var s string
+	if err := Decode(int64(1), &s); err == nil {
+		t.Errorf("expected a type mismatch error")
+	}
+}
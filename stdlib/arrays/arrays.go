@@ -11,10 +11,13 @@ import (
 
 type Arrays struct {
 	DispatchTable map[string]common.Sanitizer
+
+	caller common.Caller
+	ops    common.Operators
 }
 
-func New() *Arrays {
-	a := &Arrays{}
+func New(caller common.Caller, ops common.Operators) *Arrays {
+	a := &Arrays{caller: caller, ops: ops}
 	a.DispatchTable = map[string]common.Sanitizer{
 		"len":    a.lenSanitizer,
 		"push":   a.pushSanitizer,
@@ -23,6 +26,36 @@ func New() *Arrays {
 		"erase":  a.eraseSanitizer,
 		"concat": a.concatSanitizer,
 		"copy":   a.copySanitizer,
+
+		"map":       a.mapSanitizer,
+		"filter":    a.filterSanitizer,
+		"reduce":    a.reduceSanitizer,
+		"each":      a.eachSanitizer,
+		"find":      a.findSanitizer,
+		"findIndex": a.findIndexSanitizer,
+		"any":       a.anySanitizer,
+		"all":       a.allSanitizer,
+		"flatMap":   a.flatMapSanitizer,
+		"zip":       a.zipSanitizer,
+		"enumerate": a.enumerateSanitizer,
+		"groupBy":   a.groupBySanitizer,
+		"partition": a.partitionSanitizer,
+		"chunk":     a.chunkSanitizer,
+		"uniq":      a.uniqSanitizer,
+		"reverse":   a.reverseSanitizer,
+
+		"sort":         a.sortSanitizer,
+		"sortBy":       a.sortBySanitizer,
+		"sortWith":     a.sortWithSanitizer,
+		"binarySearch": a.binarySearchSanitizer,
+		"indexOf":      a.indexOfSanitizer,
+		"contains":     a.containsSanitizer,
+		"min":          a.minSanitizer,
+		"max":          a.maxSanitizer,
+		"sum":          a.sumSanitizer,
+		"union":        a.unionSanitizer,
+		"intersection": a.intersectionSanitizer,
+		"difference":   a.differenceSanitizer,
 	}
 
 	return a
@@ -61,7 +94,7 @@ func (a *Arrays) pushSanitizer(args ...any) (any, error) {
 			builtin.TypeStr(args[0]))
 	}
 
-	return nil, arr.Insert(int(arr.Len()), args[0])
+	return nil, arr.Insert(int(arr.Len()), args[1])
 }
 
 func (a *Arrays) popSanitizer(args ...any) (any, error) {
@@ -0,0 +1,451 @@
+package arrays
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+)
+
+func arrayAndFunction(name string, args []any) (*objects.ArrayObject, any, error) {
+	if err := common.ArgCount(name, args, 2); err != nil {
+		return nil, nil, err
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s expects first argument to be array, got=%s",
+			name, builtin.TypeStr(args[0]))
+	}
+
+	if builtin.TypeStr(args[1]) != "function" {
+		return nil, nil, fmt.Errorf("%s expects second argument to be function, got=%s",
+			name, builtin.TypeStr(args[1]))
+	}
+
+	return arr, args[1], nil
+}
+
+func (a *Arrays) callElem(function any, elem any, index int) (any, error) {
+	if fn, ok := function.(objects.FuncObject); ok && len(fn.Function.Parameters) == 2 {
+		return a.caller.Call(function, elem, int64(index))
+	}
+
+	return a.caller.Call(function, elem)
+}
+
+func (a *Arrays) mapSanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("map", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{List: make([]any, 0, len(arr.List))}
+	for i, elem := range arr.List {
+		value, err := a.callElem(fn, elem, i)
+		if err != nil {
+			return nil, err
+		}
+		result.List = append(result.List, value)
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) filterSanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("filter", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{}
+	for i, elem := range arr.List {
+		keep, err := a.callElem(fn, elem, i)
+		if err != nil {
+			return nil, err
+		}
+		if common.IsTruthy(keep) {
+			result.List = append(result.List, elem)
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) reduceSanitizer(args ...any) (any, error) {
+	if err := common.ArgRange("reduce", args, 2, 3); err != nil {
+		return nil, err
+	}
+
+	arr, fn, err := arrayAndFunction("reduce", args[:2])
+	if err != nil {
+		return nil, err
+	}
+
+	list := arr.List
+	var acc any
+	if len(args) == 3 {
+		acc = args[2]
+	} else {
+		if len(list) == 0 {
+			return nil, fmt.Errorf("reduce of empty array with no initial value")
+		}
+		acc, list = list[0], list[1:]
+	}
+
+	for _, elem := range list {
+		acc, err = a.caller.Call(fn, acc, elem)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return acc, nil
+}
+
+func (a *Arrays) eachSanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("each", args)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, elem := range arr.List {
+		if _, err := a.callElem(fn, elem, i); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// returns the index of the first element the predicate holds for or -1
+func (a *Arrays) findFirst(name string, args []any) (*objects.ArrayObject, int, error) {
+	arr, fn, err := arrayAndFunction(name, args)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	for i, elem := range arr.List {
+		found, err := a.callElem(fn, elem, i)
+		if err != nil {
+			return nil, -1, err
+		}
+		if common.IsTruthy(found) {
+			return arr, i, nil
+		}
+	}
+
+	return arr, -1, nil
+}
+
+func (a *Arrays) findSanitizer(args ...any) (any, error) {
+	arr, index, err := a.findFirst("find", args)
+	if err != nil || index < 0 {
+		return nil, err
+	}
+
+	return arr.List[index], nil
+}
+
+func (a *Arrays) findIndexSanitizer(args ...any) (any, error) {
+	_, index, err := a.findFirst("findIndex", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return int64(index), nil
+}
+
+func (a *Arrays) anySanitizer(args ...any) (any, error) {
+	_, index, err := a.findFirst("any", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return index >= 0, nil
+}
+
+func (a *Arrays) allSanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("all", args)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, elem := range arr.List {
+		holds, err := a.callElem(fn, elem, i)
+		if err != nil {
+			return nil, err
+		}
+		if !common.IsTruthy(holds) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (a *Arrays) flatMapSanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("flatMap", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{}
+	for i, elem := range arr.List {
+		value, err := a.callElem(fn, elem, i)
+		if err != nil {
+			return nil, err
+		}
+		// arrays are flattened by one level, other values are kept
+		if inner, ok := value.(*objects.ArrayObject); ok {
+			result.List = append(result.List, inner.List...)
+		} else {
+			result.List = append(result.List, value)
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) zipSanitizer(args ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("zip expects at least one argument")
+	}
+
+	size := math.MaxInt
+	for i, arg := range args {
+		arr, ok := arg.(*objects.ArrayObject)
+		if !ok {
+			return nil, fmt.Errorf("zip expects all arguments to be array, arg=%d got=%s",
+				i+1, builtin.TypeStr(arg))
+		}
+		size = min(size, len(arr.List))
+	}
+
+	// the result is as long as the shortest array
+	result := &objects.ArrayObject{List: make([]any, size)}
+	for i := range size {
+		tuple := &objects.ArrayObject{List: make([]any, len(args))}
+		for j, arg := range args {
+			tuple.List[j] = arg.(*objects.ArrayObject).List[i]
+		}
+		result.List[i] = tuple
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) enumerateSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("enumerate", args, 1); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("enumerate expects argument to be array, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	result := &objects.ArrayObject{List: make([]any, len(arr.List))}
+	for i, elem := range arr.List {
+		result.List[i] = &objects.ArrayObject{List: []any{int64(i), elem}}
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) groupBySanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("groupBy", args)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := &objects.MapObject{}
+	for i, elem := range arr.List {
+		key, err := a.callElem(fn, elem, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := builtin.CheckKey(key); err != nil {
+			return nil, err
+		}
+
+		group, ok := groups.Get(key)
+		if !ok {
+			group = &objects.ArrayObject{}
+			groups.Set(key, group)
+		}
+		group.(*objects.ArrayObject).List = append(group.(*objects.ArrayObject).List, elem)
+	}
+
+	return groups, nil
+}
+
+func (a *Arrays) partitionSanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("partition", args)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, rest := &objects.ArrayObject{}, &objects.ArrayObject{}
+	for i, elem := range arr.List {
+		holds, err := a.callElem(fn, elem, i)
+		if err != nil {
+			return nil, err
+		}
+		if common.IsTruthy(holds) {
+			matched.List = append(matched.List, elem)
+		} else {
+			rest.List = append(rest.List, elem)
+		}
+	}
+
+	return &objects.ArrayObject{List: []any{matched, rest}}, nil
+}
+
+func (a *Arrays) chunkSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("chunk", args, 2); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("chunk expects first argument to be array, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	size, ok := args[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("chunk expects second argument to be int, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("chunk expects a positive size, got=%d", size)
+	}
+
+	result := &objects.ArrayObject{}
+	for part := range slices.Chunk(arr.List, int(min(size, int64(math.MaxInt)))) {
+		result.List = append(result.List, &objects.ArrayObject{List: slices.Clone(part)})
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) uniqSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("uniq", args, 1); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("uniq expects argument to be array, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	result := &objects.ArrayObject{}
+	seen, _ := a.newValueSet(nil)
+	for _, elem := range arr.List {
+		added, err := seen.add(elem)
+		if err != nil {
+			return nil, err
+		}
+		if added {
+			result.List = append(result.List, elem)
+		}
+	}
+
+	return result, nil
+}
+
+// valueSet holds values up to RoLang equality. scalars are found through
+// their key, everything else by comparing against the values added so far
+type valueSet struct {
+	ops        common.Operators
+	scalars    map[any]bool
+	composites []any
+}
+
+func (a *Arrays) newValueSet(values []any) (*valueSet, error) {
+	set := &valueSet{ops: a.ops, scalars: map[any]bool{}}
+	for _, value := range values {
+		if _, err := set.add(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+func (s *valueSet) has(value any) (bool, error) {
+	if key, ok := scalarKey(value); ok {
+		return s.scalars[key], nil
+	}
+
+	for _, kept := range s.composites {
+		equal, err := s.ops.Equal(kept, value)
+		if err != nil {
+			return false, err
+		}
+		if equal {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// adds the value and reports whether it was not in the set before
+func (s *valueSet) add(value any) (bool, error) {
+	found, err := s.has(value)
+	if err != nil || found {
+		return false, err
+	}
+
+	if key, ok := scalarKey(value); ok {
+		s.scalars[key] = true
+	} else {
+		s.composites = append(s.composites, value)
+	}
+
+	return true, nil
+}
+
+// returns a key which is the same for scalars that are equal in RoLang,
+// floats holding a whole number share their key with the integer
+func scalarKey(value any) (any, bool) {
+	switch v := value.(type) {
+	case nil, int64, string, bool:
+		return v, true
+	case *big.Int:
+		return objects.HashKey(v), true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+		return v, true
+	}
+
+	return nil, false
+}
+
+func (a *Arrays) reverseSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("reverse", args, 1); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("reverse expects argument to be array, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	result := &objects.ArrayObject{List: slices.Clone(arr.List)}
+	slices.Reverse(result.List)
+
+	return result, nil
+}
@@ -0,0 +1,376 @@
+package arrays
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+	"slices"
+)
+
+func singleArray(name string, args []any) (*objects.ArrayObject, error) {
+	if err := common.ArgCount(name, args, 1); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("%s expects argument to be array, got=%s",
+			name, builtin.TypeStr(args[0]))
+	}
+
+	return arr, nil
+}
+
+// compares with `<`, remembering the first error since
+// the sort functions of the slices package cannot fail
+func (a *Arrays) compare(err *error) func(l, r any) int {
+	return func(l, r any) int {
+		if *err != nil {
+			return 0
+		}
+
+		less, e := a.ops.Less(l, r)
+		if e != nil {
+			*err = e
+			return 0
+		}
+		if less {
+			return -1
+		}
+
+		greater, e := a.ops.Less(r, l)
+		if e != nil {
+			*err = e
+			return 0
+		}
+		if greater {
+			return 1
+		}
+
+		return 0
+	}
+}
+
+func (a *Arrays) sortSanitizer(args ...any) (any, error) {
+	arr, err := singleArray("sort", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{List: slices.Clone(arr.List)}
+	slices.SortStableFunc(result.List, a.compare(&err))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) sortBySanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("sortBy", args)
+	if err != nil {
+		return nil, err
+	}
+
+	// every key is computed once and sorted along with its element
+	type keyed struct {
+		key  any
+		elem any
+	}
+	pairs := make([]keyed, len(arr.List))
+	for i, elem := range arr.List {
+		key, err := a.callElem(fn, elem, i)
+		if err != nil {
+			return nil, err
+		}
+		pairs[i] = keyed{key, elem}
+	}
+
+	cmp := a.compare(&err)
+	slices.SortStableFunc(pairs, func(l, r keyed) int {
+		return cmp(l.key, r.key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{List: make([]any, len(pairs))}
+	for i, pair := range pairs {
+		result.List[i] = pair.elem
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) sortWithSanitizer(args ...any) (any, error) {
+	arr, fn, err := arrayAndFunction("sortWith", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{List: slices.Clone(arr.List)}
+	// the comparator returns a negative number if its first argument
+	// goes first, a positive one if it goes last and 0 if they are equal
+	slices.SortStableFunc(result.List, func(l, r any) int {
+		if err != nil {
+			return 0
+		}
+
+		var order any
+		order, err = a.caller.Call(fn, l, r)
+		if err != nil {
+			return 0
+		}
+
+		switch v := order.(type) {
+		case int64:
+			return int(min(max(v, -1), 1))
+		case float64:
+			switch {
+			case v < 0:
+				return -1
+			case v > 0:
+				return 1
+			}
+			return 0
+		default:
+			err = fmt.Errorf("sortWith expects comparator to return a number, got=%s",
+				builtin.TypeStr(order))
+			return 0
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func arrayAndValue(name string, args []any) (*objects.ArrayObject, any, error) {
+	if err := common.ArgCount(name, args, 2); err != nil {
+		return nil, nil, err
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s expects first argument to be array, got=%s",
+			name, builtin.TypeStr(args[0]))
+	}
+
+	return arr, args[1], nil
+}
+
+// expects the array to be sorted and returns the
+// index of an element equal to the value or -1
+func (a *Arrays) binarySearchSanitizer(args ...any) (any, error) {
+	arr, value, err := arrayAndValue("binarySearch", args)
+	if err != nil {
+		return nil, err
+	}
+
+	index, found := slices.BinarySearchFunc(arr.List, value, a.compare(&err))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return int64(-1), nil
+	}
+
+	return int64(index), nil
+}
+
+func (a *Arrays) indexOf(arr *objects.ArrayObject, value any) (int, error) {
+	for i, elem := range arr.List {
+		equal, err := a.ops.Equal(elem, value)
+		if err != nil {
+			return -1, err
+		}
+		if equal {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+func (a *Arrays) indexOfSanitizer(args ...any) (any, error) {
+	arr, value, err := arrayAndValue("indexOf", args)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := a.indexOf(arr, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return int64(index), nil
+}
+
+func (a *Arrays) containsSanitizer(args ...any) (any, error) {
+	arr, value, err := arrayAndValue("contains", args)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := a.indexOf(arr, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return index >= 0, nil
+}
+
+// returns the first element that no other element is
+// ordered before, or null for an empty array
+func (a *Arrays) extreme(name string, args []any, before func(l, r any) (bool, error)) (any, error) {
+	arr, err := singleArray(name, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(arr.List) == 0 {
+		return nil, nil
+	}
+
+	result := arr.List[0]
+	for _, elem := range arr.List[1:] {
+		ok, err := before(elem, result)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = elem
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) minSanitizer(args ...any) (any, error) {
+	return a.extreme("min", args, a.ops.Less)
+}
+
+func (a *Arrays) maxSanitizer(args ...any) (any, error) {
+	return a.extreme("max", args, func(l, r any) (bool, error) {
+		return a.ops.Less(r, l)
+	})
+}
+
+func (a *Arrays) sumSanitizer(args ...any) (any, error) {
+	arr, err := singleArray("sum", args)
+	if err != nil {
+		return nil, err
+	}
+
+	var sum any = int64(0)
+	for _, elem := range arr.List {
+		switch builtin.TypeStr(elem) {
+		case "int", "float":
+		default:
+			return nil, fmt.Errorf("sum expects numbers, got=%s", builtin.TypeStr(elem))
+		}
+
+		sum, err = a.ops.Add(sum, elem)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sum, nil
+}
+
+func arrays(name string, args []any) ([]*objects.ArrayObject, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%s expects at least two arguments, got=%d", name, len(args))
+	}
+
+	arrs := make([]*objects.ArrayObject, len(args))
+	for i, arg := range args {
+		arr, ok := arg.(*objects.ArrayObject)
+		if !ok {
+			return nil, fmt.Errorf("%s expects all arguments to be array, arg=%d got=%s",
+				name, i+1, builtin.TypeStr(arg))
+		}
+		arrs[i] = arr
+	}
+
+	return arrs, nil
+}
+
+// set operations compare elements with `==` and return elements
+// without duplicates, in the order they are first found
+
+func (a *Arrays) unionSanitizer(args ...any) (any, error) {
+	arrs, err := arrays("union", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{}
+	seen, _ := a.newValueSet(nil)
+	for _, arr := range arrs {
+		for _, elem := range arr.List {
+			added, err := seen.add(elem)
+			if err != nil {
+				return nil, err
+			}
+			if added {
+				result.List = append(result.List, elem)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// keeps the unique elements of the first array which are
+// in every other array, or in none of them
+func (a *Arrays) filterSets(name string, args []any, keepFound bool) (any, error) {
+	arrs, err := arrays(name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	others := make([]*valueSet, len(arrs)-1)
+	for i, arr := range arrs[1:] {
+		others[i], err = a.newValueSet(arr.List)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &objects.ArrayObject{}
+	seen, _ := a.newValueSet(nil)
+next:
+	for _, elem := range arrs[0].List {
+		for _, other := range others {
+			found, err := other.has(elem)
+			if err != nil {
+				return nil, err
+			}
+			if found != keepFound {
+				continue next
+			}
+		}
+
+		added, err := seen.add(elem)
+		if err != nil {
+			return nil, err
+		}
+		if added {
+			result.List = append(result.List, elem)
+		}
+	}
+
+	return result, nil
+}
+
+func (a *Arrays) intersectionSanitizer(args ...any) (any, error) {
+	return a.filterSets("intersection", args, true)
+}
+
+func (a *Arrays) differenceSanitizer(args ...any) (any, error) {
+	return a.filterSets("difference", args, false)
+}
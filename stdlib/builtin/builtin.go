@@ -4,19 +4,34 @@ import (
 	"RoLang/evaluator/objects"
 	"RoLang/stdlib/common"
 
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"math/big"
 )
 
 type BuiltIn struct {
 	DispatchTable map[string]common.Sanitizer
+
+	from func(any) string
+	ops  common.Operators
 }
 
-func New() *BuiltIn {
-	return &BuiltIn{
-		DispatchTable: map[string]common.Sanitizer{
-			"type": typeStrSanitizer,
-		},
+// New creates the builtin functions, from gives the string
+// representation of values used by `str`
+func New(from func(any) string, ops common.Operators) *BuiltIn {
+	b := &BuiltIn{from: from, ops: ops}
+	b.DispatchTable = map[string]common.Sanitizer{
+		"type":    typeStrSanitizer,
+		"int":     intSanitizer,
+		"float":   floatSanitizer,
+		"bool":    boolSanitizer,
+		"str":     b.strSanitizer,
+		"compare": b.compareSanitizer,
+		"hash":    hashSanitizer,
 	}
+
+	return b
 }
 
 func (b *BuiltIn) Dispatcher(name string) (common.Sanitizer, error) {
@@ -39,7 +54,7 @@ func typeStrSanitizer(args ...any) (any, error) {
 
 func TypeStr(value any) string {
 	switch value.(type) {
-	case int64:
+	case int64, *big.Int, objects.BigIntKey:
 		return "int"
 	case float64:
 		return "float"
@@ -51,11 +66,84 @@ func TypeStr(value any) string {
 		return "map"
 	case *objects.ArrayObject:
 		return "array"
-	case *objects.FuncObject:
+	case *objects.SetObject:
+		return "set"
+	case *objects.DequeObject:
+		return "deque"
+	case *objects.HeapObject:
+		return "heap"
+	case objects.FuncObject, common.Sanitizer:
 		return "function"
+	case *objects.FileObject:
+		return "file"
+	case *objects.RegexObject:
+		return "regex"
 	case nil:
 		return "null"
 	default:
 		return "<unknown>"
 	}
 }
+
+// CheckKey reports whether a value may be used as a map key, arrays
+// may be keys when all their elements may be and they are not nested
+// deeper than objects.MaxNesting
+func CheckKey(key any) error {
+	return checkKey(key, map[*objects.ArrayObject]bool{})
+}
+
+func checkKey(key any, visiting map[*objects.ArrayObject]bool) error {
+	switch v := key.(type) {
+	case int64, *big.Int, float64, string, bool:
+		return nil
+	case *objects.ArrayObject:
+		if visiting[v] {
+			return errors.New("cannot use an array containing itself as key")
+		}
+		if len(visiting) >= objects.MaxNesting {
+			return fmt.Errorf("%w (%d levels)", objects.ErrNesting, objects.MaxNesting)
+		}
+		visiting[v] = true
+		defer delete(visiting, v)
+
+		for _, elem := range v.List {
+			if err := checkKey(elem, visiting); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("only int, float, string, bool and array is allowed as key. got=%s",
+			TypeStr(key))
+	}
+}
+
+// compare returns -1 when the first value is smaller, 1 when it is
+// greater and 0 otherwise, with the same ordering as `<` and `>`
+func (b *BuiltIn) compareSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("compare", args, 2); err != nil {
+		return nil, err
+	}
+
+	order, err := b.ops.Compare(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return int64(order), nil
+}
+
+// hash returns the same int for values that are the same map key,
+// from one run to the next
+func hashSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("hash", args, 1); err != nil {
+		return nil, err
+	}
+	if err := CheckKey(args[0]); err != nil {
+		return nil, fmt.Errorf("hash: %w", err)
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(objects.EncodeKey(args[0])))
+	return int64(h.Sum64()), nil
+}
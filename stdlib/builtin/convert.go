@@ -0,0 +1,163 @@
+package builtin
+
+import (
+	"RoLang/stdlib/common"
+
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// returns the base passed as the optional second argument. 0
+// detects the base from a `0b`, `0o` or `0x` prefix
+func baseArg(name string, args []any) (int, error) {
+	if err := common.ArgRange("`"+name+"`", args, 1, 2); err != nil {
+		return 0, err
+	}
+	if len(args) == 1 {
+		return 10, nil
+	}
+
+	base, ok := args[1].(int64)
+	if !ok {
+		return 0, fmt.Errorf("`%s` expects base to be int, got=%s", name, TypeStr(args[1]))
+	}
+	if base != 0 && (base < 2 || base > 36) {
+		return 0, fmt.Errorf("`%s` expects base to be 0 or between 2 and 36, got=%d", name, base)
+	}
+
+	return int(base), nil
+}
+
+func intSanitizer(args ...any) (any, error) {
+	base, err := baseArg("int", args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 2 {
+		if _, ok := args[0].(string); !ok {
+			return nil, fmt.Errorf("`int` accepts a base only for strings, got=%s", TypeStr(args[0]))
+		}
+	}
+
+	switch v := args[0].(type) {
+	case int64, *big.Int:
+		return v, nil
+	case float64:
+		// floats are truncated toward zero
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("cannot convert %v to int", v)
+		}
+		if v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), nil
+		}
+		n, _ := big.NewFloat(v).Int(nil)
+		return n, nil
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case string:
+		return ParseInt(v, base)
+	default:
+		return nil, fmt.Errorf("cannot convert %s to int", TypeStr(v))
+	}
+}
+
+// ParseInt parses an integer in the given base, returning
+// a *big.Int only when it does not fit in an int64
+func ParseInt(str string, base int) (any, error) {
+	trimmed := strings.TrimSpace(str)
+	if n, err := strconv.ParseInt(trimmed, base, 64); err == nil {
+		return n, nil
+	}
+
+	n, ok := new(big.Int).SetString(trimmed, base)
+	if !ok {
+		if base == 0 {
+			return nil, fmt.Errorf("invalid int literal %q", str)
+		}
+		return nil, fmt.Errorf("invalid int literal %q in base %d", str, base)
+	}
+	if n.IsInt64() {
+		return n.Int64(), nil
+	}
+
+	return n, nil
+}
+
+func floatSanitizer(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("`float` expects only a single argument, got=%d", len(args))
+	}
+
+	switch v := args[0].(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f, nil
+	case bool:
+		if v {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float literal %q", v)
+		}
+		return f, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %s to float", TypeStr(v))
+	}
+}
+
+// strings must spell "true" or "false", other
+// values are converted by their truthiness
+func boolSanitizer(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("`bool` expects only a single argument, got=%d", len(args))
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return common.IsTruthy(args[0]), nil
+	}
+
+	switch strings.TrimSpace(str) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return nil, fmt.Errorf("invalid bool literal %q", str)
+	}
+}
+
+func (b *BuiltIn) strSanitizer(args ...any) (any, error) {
+	base, err := baseArg("str", args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 1 {
+		return b.from(args[0]), nil
+	}
+
+	if base == 0 {
+		return nil, fmt.Errorf("`str` expects base to be between 2 and 36, got=0")
+	}
+	switch v := args[0].(type) {
+	case int64:
+		return strconv.FormatInt(v, base), nil
+	case *big.Int:
+		return v.Text(base), nil
+	default:
+		return nil, fmt.Errorf("`str` accepts a base only for ints, got=%s", TypeStr(v))
+	}
+}
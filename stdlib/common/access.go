@@ -0,0 +1,93 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FSAccess is the file system capability granted to scripts.
+// The zero value grants full access
+type FSAccess struct {
+	// Root confines every path to this directory, relative paths
+	// are resolved against it. Empty means no confinement
+	Root string
+	// ReadOnly forbids every operation that modifies the file system
+	ReadOnly bool
+}
+
+// Resolve checks that the operation `op` may use the path and returns
+// the path it should be performed on
+func (a FSAccess) Resolve(op string, path string, write bool) (string, error) {
+	if write && a.ReadOnly {
+		return "", fmt.Errorf("permission denied: %s %q: file system is read-only", op, path)
+	}
+	if a.Root == "" {
+		return path, nil
+	}
+
+	root, err := filepath.Abs(a.Root)
+	if err != nil {
+		return "", err
+	}
+
+	resolved := path
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(root, resolved)
+	}
+	resolved = filepath.Clean(resolved)
+
+	if !within(root, resolved) || !within(realPath(root), realPath(resolved)) {
+		return "", fmt.Errorf("permission denied: %s %q: path is outside of %q", op, path, a.Root)
+	}
+
+	return resolved, nil
+}
+
+func within(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// the number of links followed before a path is given up on
+const maxLinks = 255
+
+// follows symbolic links in the longest existing prefix of the path
+// so that links cannot be used to escape the root. links to a target
+// which does not exist are followed too, as writing through them would
+// create it. an empty result is never within the root
+func realPath(path string) string {
+	rest := ""
+	for links := 0; ; {
+		real, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(real, rest)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return path
+		}
+
+		if info, err := os.Lstat(path); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil || links == maxLinks {
+				return ""
+			}
+			links++
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(path), target)
+			}
+			path = filepath.Clean(target)
+			continue
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest)
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var numbers = []string{"no", "one", "two", "three", "four", "five"}
+
+var ordinals = []string{"first", "second", "third", "fourth", "fifth"}
+
+func number(n int) string {
+	if n >= 0 && n < len(numbers) {
+		return numbers[n]
+	}
+
+	return strconv.Itoa(n)
+}
+
+// Count spells a number of arguments in error messages, like "two arguments"
+func Count(n int) string {
+	if n == 1 {
+		return "one argument"
+	}
+
+	return number(n) + " arguments"
+}
+
+// ArgCount reports an error when a function gets other than count arguments
+func ArgCount(name string, args []any, count int) error {
+	if len(args) != count {
+		return fmt.Errorf("%s expects %s, got=%d", name, Count(count), len(args))
+	}
+
+	return nil
+}
+
+// ArgRange reports an error when a function gets fewer
+// than least or more than most arguments
+func ArgRange(name string, args []any, least int, most int) error {
+	if least == most {
+		return ArgCount(name, args, least)
+	}
+	if len(args) < least || len(args) > most {
+		return fmt.Errorf("%s expects %s or %s, got=%d", name, number(least), Count(most), len(args))
+	}
+
+	return nil
+}
+
+// Ordinal names the argument at index i in error messages, like
+// "second argument", or just "argument" when there is only one
+func Ordinal(i int, count int) string {
+	if count == 1 {
+		return "argument"
+	}
+	if i < len(ordinals) {
+		return ordinals[i] + " argument"
+	}
+
+	return "argument " + strconv.Itoa(i+1)
+}
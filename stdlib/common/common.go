@@ -1,3 +1,53 @@
 package common
 
+import "fmt"
+
 type Sanitizer func(...any) (any, error)
+
+// Caller calls RoLang functions, closures and stdlib functions alike,
+// from Go. Errors raised by the function are returned as they are so
+// they keep the location where they happened
+type Caller interface {
+	Call(function any, args ...any) (any, error)
+}
+
+// Operators applies RoLang's operators to values, with
+// the same promotion rules and errors as in scripts
+type Operators interface {
+	Equal(left, right any) (bool, error)
+	Less(left, right any) (bool, error)
+	// Compare returns -1, 0 or 1 as left is smaller,
+	// equal to or greater than right
+	Compare(left, right any) (int, error)
+	Add(left, right any) (any, error)
+}
+
+// Exit is returned by functions which end the script, like os.exit.
+// It unwinds the script which then ends with the code, just like
+// a top level return does
+type Exit struct {
+	Code int64
+}
+
+func (e Exit) Error() string {
+	return fmt.Sprintf("exit %d", e.Code)
+}
+
+// IsTruthy reports whether a value counts as true in conditions,
+// false, null, 0, 0.0 and "" are false while everything else is true
+func IsTruthy(value any) bool {
+	switch value {
+	case false:
+		fallthrough
+	case nil:
+		fallthrough
+	case int64(0):
+		fallthrough
+	case 0.0:
+		fallthrough
+	case "":
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,145 @@
+package deques
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+)
+
+type Deques struct {
+	DispatchTable map[string]common.Sanitizer
+}
+
+func New() *Deques {
+	d := &Deques{}
+	d.DispatchTable = map[string]common.Sanitizer{
+		"new":       d.newSanitizer,
+		"len":       d.lenSanitizer,
+		"pushBack":  d.pushSanitizer("pushBack", (*objects.DequeObject).PushBack),
+		"pushFront": d.pushSanitizer("pushFront", (*objects.DequeObject).PushFront),
+		"popBack":   d.popSanitizer("popBack", (*objects.DequeObject).PopBack),
+		"popFront":  d.popSanitizer("popFront", (*objects.DequeObject).PopFront),
+		"peekBack":  d.peekSanitizer("peekBack", true),
+		"peekFront": d.peekSanitizer("peekFront", false),
+		"toArray":   d.toArraySanitizer,
+	}
+
+	return d
+}
+
+func (d *Deques) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := d.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in deques module", name)
+	}
+
+	return sanitizer, nil
+}
+
+// checks the number of arguments and that the first one is a deque
+func dequeArgs(name string, args []any, count int) (*objects.DequeObject, error) {
+	if err := common.ArgCount(name, args, count); err != nil {
+		return nil, err
+	}
+
+	deque, ok := args[0].(*objects.DequeObject)
+	if !ok {
+		return nil, fmt.Errorf("%s expects %s to be deque, got=%s",
+			name, common.Ordinal(0, count), builtin.TypeStr(args[0]))
+	}
+
+	return deque, nil
+}
+
+// new creates an empty deque, or a deque of the elements of an array
+func (d *Deques) newSanitizer(args ...any) (any, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("new expects at most one argument, got=%d", len(args))
+	}
+
+	deque := &objects.DequeObject{}
+	if len(args) == 0 {
+		return deque, nil
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("new expects argument to be array, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	for _, elem := range arr.List {
+		deque.PushBack(elem)
+	}
+
+	return deque, nil
+}
+
+func (d *Deques) lenSanitizer(args ...any) (any, error) {
+	deque, err := dequeArgs("len", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return deque.Len(), nil
+}
+
+func (d *Deques) pushSanitizer(name string, push func(*objects.DequeObject, any)) common.Sanitizer {
+	return func(args ...any) (any, error) {
+		deque, err := dequeArgs(name, args, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		push(deque, args[1])
+		return nil, nil
+	}
+}
+
+func (d *Deques) popSanitizer(name string, pop func(*objects.DequeObject) (any, bool)) common.Sanitizer {
+	return func(args ...any) (any, error) {
+		deque, err := dequeArgs(name, args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		elem, ok := pop(deque)
+		if !ok {
+			return nil, fmt.Errorf("%s from an empty deque", name)
+		}
+
+		return elem, nil
+	}
+}
+
+// the peek functions return an element at one end without removing it
+func (d *Deques) peekSanitizer(name string, back bool) common.Sanitizer {
+	return func(args ...any) (any, error) {
+		deque, err := dequeArgs(name, args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		index := 0
+		if back {
+			index = int(deque.Len()) - 1
+		}
+		elem, ok := deque.At(index)
+		if !ok {
+			return nil, fmt.Errorf("%s from an empty deque", name)
+		}
+
+		return elem, nil
+	}
+}
+
+// toArray returns the elements from front to back
+func (d *Deques) toArraySanitizer(args ...any) (any, error) {
+	deque, err := dequeArgs("toArray", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &objects.ArrayObject{List: deque.List()}, nil
+}
@@ -0,0 +1,371 @@
+package fs
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+	rostrings "RoLang/stdlib/strings"
+
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"os"
+	"strings"
+)
+
+type Fs struct {
+	DispatchTable map[string]common.Sanitizer
+
+	access common.FSAccess
+}
+
+// New creates the fs module confined to the given access
+func New(access common.FSAccess) *Fs {
+	f := &Fs{access: access}
+	f.DispatchTable = map[string]common.Sanitizer{
+		"readFile":   f.readFileSanitizer,
+		"writeFile":  f.writeFileSanitizer,
+		"appendFile": f.appendFileSanitizer,
+		"exists":     f.existsSanitizer,
+		"listDir":    f.listDirSanitizer,
+		"mkdirAll":   f.mkdirAllSanitizer,
+		"remove":     f.removeSanitizer,
+		"rename":     f.renameSanitizer,
+		"stat":       f.statSanitizer,
+		"open":       f.openSanitizer,
+		"readLine":   f.readLineSanitizer,
+		"write":      f.writeSanitizer,
+		"close":      f.closeSanitizer,
+	}
+
+	return f
+}
+
+func (f *Fs) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := f.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in fs module", name)
+	}
+
+	return sanitizer, nil
+}
+
+// pathError replaces the os error's own operation and
+// path, which may have been resolved inside the root
+func pathError(op string, path string, err error) error {
+	var pathErr *iofs.PathError
+	var linkErr *os.LinkError
+	switch {
+	case errors.As(err, &pathErr):
+		err = pathErr.Err
+	case errors.As(err, &linkErr):
+		err = linkErr.Err
+	}
+
+	return fmt.Errorf("%s %q: %w", op, path, err)
+}
+
+// checks that the arguments are strings and returns the
+// first one resolved as a path the operation may use
+func (f *Fs) pathArgs(name string, args []any, count int, write bool) (string, []string, error) {
+	if err := common.ArgCount(name, args, count); err != nil {
+		return "", nil, err
+	}
+
+	strs := make([]string, count)
+	for i, arg := range args {
+		str, ok := arg.(string)
+		if !ok {
+			return "", nil, fmt.Errorf("%s expects %s to be string, got=%s",
+				name, common.Ordinal(i, count), builtin.TypeStr(arg))
+		}
+		strs[i] = str
+	}
+
+	path, err := f.access.Resolve(name, strs[0], write)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return path, strs, nil
+}
+
+func (f *Fs) readFileSanitizer(args ...any) (any, error) {
+	path, strs, err := f.pathArgs("readFile", args, 1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, pathError("readFile", strs[0], err)
+	}
+
+	return string(content), nil
+}
+
+func (f *Fs) writeFileSanitizer(args ...any) (any, error) {
+	path, strs, err := f.pathArgs("writeFile", args, 2, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(path, []byte(strs[1]), 0o644); err != nil {
+		return nil, pathError("writeFile", strs[0], err)
+	}
+
+	return nil, nil
+}
+
+func (f *Fs) appendFileSanitizer(args ...any) (any, error) {
+	path, strs, err := f.pathArgs("appendFile", args, 2, true)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, pathError("appendFile", strs[0], err)
+	}
+	_, err = file.WriteString(strs[1])
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, pathError("appendFile", strs[0], err)
+	}
+
+	return nil, nil
+}
+
+func (f *Fs) existsSanitizer(args ...any) (any, error) {
+	path, strs, err := f.pathArgs("exists", args, 1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = os.Stat(path)
+	if errors.Is(err, iofs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return nil, pathError("exists", strs[0], err)
+	}
+
+	return true, nil
+}
+
+// returns the sorted names of the entries in a directory
+func (f *Fs) listDirSanitizer(args ...any) (any, error) {
+	path, strs, err := f.pathArgs("listDir", args, 1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, pathError("listDir", strs[0], err)
+	}
+
+	result := &objects.ArrayObject{List: make([]any, len(entries))}
+	for i, entry := range entries {
+		result.List[i] = entry.Name()
+	}
+
+	return result, nil
+}
+
+func (f *Fs) mkdirAllSanitizer(args ...any) (any, error) {
+	path, strs, err := f.pathArgs("mkdirAll", args, 1, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, pathError("mkdirAll", strs[0], err)
+	}
+
+	return nil, nil
+}
+
+// removes a file or an empty directory
+func (f *Fs) removeSanitizer(args ...any) (any, error) {
+	path, strs, err := f.pathArgs("remove", args, 1, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(path); err != nil {
+		return nil, pathError("remove", strs[0], err)
+	}
+
+	return nil, nil
+}
+
+func (f *Fs) renameSanitizer(args ...any) (any, error) {
+	from, strs, err := f.pathArgs("rename", args, 2, true)
+	if err != nil {
+		return nil, err
+	}
+	to, err := f.access.Resolve("rename", strs[1], true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(from, to); err != nil {
+		return nil, pathError("rename", strs[0], err)
+	}
+
+	return nil, nil
+}
+
+// returns a map with the name, size, isDir, mode and the
+// modification time in seconds since the epoch of a path
+func (f *Fs) statSanitizer(args ...any) (any, error) {
+	path, strs, err := f.pathArgs("stat", args, 1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, pathError("stat", strs[0], err)
+	}
+
+	stat := &objects.MapObject{}
+	stat.Set("name", info.Name())
+	stat.Set("size", info.Size())
+	stat.Set("isDir", info.IsDir())
+	stat.Set("mode", info.Mode().String())
+	stat.Set("modTime", info.ModTime().Unix())
+
+	return stat, nil
+}
+
+// opens a file for reading ("r", the default), for writing from
+// scratch ("w") or for appending ("a") and returns its handle
+func (f *Fs) openSanitizer(args ...any) (any, error) {
+	if err := common.ArgRange("open", args, 1, 2); err != nil {
+		return nil, err
+	}
+
+	mode := "r"
+	if len(args) == 2 {
+		var ok bool
+		mode, ok = args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("open expects second argument to be string, got=%s",
+				builtin.TypeStr(args[1]))
+		}
+		args = args[:1]
+	}
+
+	var flag int
+	switch mode {
+	case "r":
+		flag = os.O_RDONLY
+	case "w":
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	case "a":
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	default:
+		return nil, fmt.Errorf("open expects mode to be \"r\", \"w\" or \"a\", got=%q", mode)
+	}
+
+	path, strs, err := f.pathArgs("open", args, 1, mode != "r")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return nil, pathError("open", strs[0], err)
+	}
+
+	handle := &objects.FileObject{Path: strs[0], File: file}
+	if mode == "r" {
+		handle.Reader = bufio.NewReader(file)
+	}
+
+	return handle, nil
+}
+
+func handleArg(name string, args []any) (*objects.FileObject, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s expects a file argument", name)
+	}
+	handle, ok := args[0].(*objects.FileObject)
+	if !ok {
+		return nil, fmt.Errorf("%s expects first argument to be file, got=%s",
+			name, builtin.TypeStr(args[0]))
+	}
+	if handle.Closed {
+		return nil, pathError(name, handle.Path, iofs.ErrClosed)
+	}
+
+	return handle, nil
+}
+
+// reads the next line without its line break, or null at the end of the file
+func (f *Fs) readLineSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("readLine", args, 1); err != nil {
+		return nil, err
+	}
+	handle, err := handleArg("readLine", args)
+	if err != nil {
+		return nil, err
+	}
+	if handle.Reader == nil {
+		return nil, fmt.Errorf("readLine %q: file is not open for reading", handle.Path)
+	}
+
+	line, err := handle.Reader.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return nil, nil
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, pathError("readLine", handle.Path, err)
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return line, nil
+}
+
+// writes the values like io.print does
+func (f *Fs) writeSanitizer(args ...any) (any, error) {
+	handle, err := handleArg("write", args)
+	if err != nil {
+		return nil, err
+	}
+	if handle.Reader != nil {
+		return nil, fmt.Errorf("write %q: file is not open for writing", handle.Path)
+	}
+
+	for _, value := range args[1:] {
+		if _, err := handle.File.WriteString(rostrings.From(value)); err != nil {
+			return nil, pathError("write", handle.Path, err)
+		}
+	}
+
+	return nil, nil
+}
+
+func (f *Fs) closeSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("close", args, 1); err != nil {
+		return nil, err
+	}
+	handle, err := handleArg("close", args)
+	if err != nil {
+		return nil, err
+	}
+
+	handle.Closed = true
+	if err := handle.File.Close(); err != nil {
+		return nil, pathError("close", handle.Path, err)
+	}
+
+	return nil, nil
+}
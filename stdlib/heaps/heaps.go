@@ -0,0 +1,280 @@
+package heaps
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+	"slices"
+)
+
+type Heaps struct {
+	DispatchTable map[string]common.Sanitizer
+
+	caller common.Caller
+	ops    common.Operators
+}
+
+func New(caller common.Caller, ops common.Operators) *Heaps {
+	h := &Heaps{caller: caller, ops: ops}
+	h.DispatchTable = map[string]common.Sanitizer{
+		"new":     h.newSanitizer,
+		"len":     h.lenSanitizer,
+		"push":    h.pushSanitizer,
+		"pop":     h.popSanitizer,
+		"peek":    h.peekSanitizer,
+		"toArray": h.toArraySanitizer,
+	}
+
+	return h
+}
+
+func (h *Heaps) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := h.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in heaps module", name)
+	}
+
+	return sanitizer, nil
+}
+
+// checks the number of arguments and that the first one is a heap
+func heapArgs(name string, args []any, count int) (*objects.HeapObject, error) {
+	if err := common.ArgCount(name, args, count); err != nil {
+		return nil, err
+	}
+
+	heap, ok := args[0].(*objects.HeapObject)
+	if !ok {
+		return nil, fmt.Errorf("%s expects %s to be heap, got=%s",
+			name, common.Ordinal(0, count), builtin.TypeStr(args[0]))
+	}
+
+	return heap, nil
+}
+
+// reports whether a goes before b
+func (h *Heaps) less(heap *objects.HeapObject, a, b any) (bool, error) {
+	if heap.Compare == nil {
+		return h.ops.Less(a, b)
+	}
+
+	heap.Comparing = true
+	defer func() { heap.Comparing = false }()
+
+	order, err := h.caller.Call(heap.Compare, a, b)
+	if err != nil {
+		return false, err
+	}
+
+	switch v := order.(type) {
+	case int64:
+		return v < 0, nil
+	case float64:
+		return v < 0, nil
+	default:
+		return false, fmt.Errorf("heap expects comparator to return a number, got=%s",
+			builtin.TypeStr(order))
+	}
+}
+
+// a swap of two positions, kept to undo a sift
+// when the comparator fails half way
+type swap [2]int
+
+func undo(heap *objects.HeapObject, swaps []swap) {
+	for i := len(swaps) - 1; i >= 0; i-- {
+		a, b := swaps[i][0], swaps[i][1]
+		heap.List[a], heap.List[b] = heap.List[b], heap.List[a]
+	}
+}
+
+func (h *Heaps) up(heap *objects.HeapObject, i int) ([]swap, error) {
+	var swaps []swap
+	for i > 0 {
+		parent := (i - 1) / 2
+		less, err := h.less(heap, heap.List[i], heap.List[parent])
+		if err != nil || !less {
+			return swaps, err
+		}
+		heap.List[i], heap.List[parent] = heap.List[parent], heap.List[i]
+		swaps = append(swaps, swap{i, parent})
+		i = parent
+	}
+
+	return swaps, nil
+}
+
+func (h *Heaps) down(heap *objects.HeapObject, i int) ([]swap, error) {
+	var swaps []swap
+	n := len(heap.List)
+	for {
+		first := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child >= n {
+				break
+			}
+			less, err := h.less(heap, heap.List[child], heap.List[first])
+			if err != nil {
+				return swaps, err
+			}
+			if less {
+				first = child
+			}
+		}
+		if first == i {
+			return swaps, nil
+		}
+		heap.List[i], heap.List[first] = heap.List[first], heap.List[i]
+		swaps = append(swaps, swap{i, first})
+		i = first
+	}
+}
+
+// pop removes the first element, the heap is left
+// as it was when the comparator fails
+func (h *Heaps) pop(heap *objects.HeapObject) (any, error) {
+	top := heap.List[0]
+	last := len(heap.List) - 1
+	heap.List[0], heap.List[last] = heap.List[last], top
+	heap.List = heap.List[:last]
+
+	if last > 0 {
+		if swaps, err := h.down(heap, 0); err != nil {
+			// puts the first element back in its place
+			undo(heap, swaps)
+			heap.List = heap.List[:last+1]
+			heap.List[0], heap.List[last] = heap.List[last], heap.List[0]
+			return nil, err
+		}
+	}
+	// drops the reference kept past the end
+	heap.List[:last+1][last] = nil
+
+	return top, nil
+}
+
+// reports an error when a heap is changed by its own comparator
+func changing(name string, heap *objects.HeapObject) error {
+	if heap.Comparing {
+		return fmt.Errorf("%s cannot change a heap while it compares elements", name)
+	}
+
+	return nil
+}
+
+// new creates an empty heap, or a heap of the elements of an array,
+// the comparator is passed as the last argument
+func (h *Heaps) newSanitizer(args ...any) (any, error) {
+	if len(args) > 2 {
+		return nil, fmt.Errorf("new expects at most two arguments, got=%d", len(args))
+	}
+
+	heap := &objects.HeapObject{}
+	if len(args) > 0 && builtin.TypeStr(args[len(args)-1]) == "function" {
+		heap.Compare = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+	if len(args) == 2 {
+		return nil, fmt.Errorf("new expects second argument to be function, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+	if len(args) == 0 {
+		return heap, nil
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("new expects first argument to be array or function, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	heap.List = slices.Clone(arr.List)
+	for i := len(heap.List)/2 - 1; i >= 0; i-- {
+		if _, err := h.down(heap, i); err != nil {
+			return nil, err
+		}
+	}
+
+	return heap, nil
+}
+
+func (h *Heaps) lenSanitizer(args ...any) (any, error) {
+	heap, err := heapArgs("len", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return heap.Len(), nil
+}
+
+func (h *Heaps) pushSanitizer(args ...any) (any, error) {
+	heap, err := heapArgs("push", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := changing("push", heap); err != nil {
+		return nil, err
+	}
+
+	heap.List = append(heap.List, args[1])
+	if swaps, err := h.up(heap, len(heap.List)-1); err != nil {
+		// the element is not pushed when it cannot be compared
+		undo(heap, swaps)
+		heap.List[len(heap.List)-1] = nil
+		heap.List = heap.List[:len(heap.List)-1]
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// pop removes and returns the element which goes first
+func (h *Heaps) popSanitizer(args ...any) (any, error) {
+	heap, err := heapArgs("pop", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if err := changing("pop", heap); err != nil {
+		return nil, err
+	}
+	if len(heap.List) == 0 {
+		return nil, fmt.Errorf("pop from an empty heap")
+	}
+
+	return h.pop(heap)
+}
+
+// peek returns the element which goes first without removing it
+func (h *Heaps) peekSanitizer(args ...any) (any, error) {
+	heap, err := heapArgs("peek", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(heap.List) == 0 {
+		return nil, fmt.Errorf("peek from an empty heap")
+	}
+
+	return heap.List[0], nil
+}
+
+// toArray returns the elements in the order pop would
+// return them, without changing the heap
+func (h *Heaps) toArraySanitizer(args ...any) (any, error) {
+	heap, err := heapArgs("toArray", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	heapCopy := &objects.HeapObject{List: slices.Clone(heap.List), Compare: heap.Compare}
+	result := &objects.ArrayObject{List: make([]any, 0, len(heap.List))}
+	for len(heapCopy.List) > 0 {
+		elem, err := h.pop(heapCopy)
+		if err != nil {
+			return nil, err
+		}
+		result.List = append(result.List, elem)
+	}
+
+	return result, nil
+}
@@ -1,30 +1,40 @@
 package io
 
 import (
+	"RoLang/stdlib/builtin"
 	"RoLang/stdlib/common"
-	"RoLang/stdlib/strings"
+	rostrings "RoLang/stdlib/strings"
 
 	"bufio"
+	"errors"
 	"fmt"
-	"os"
+	goio "io"
+	"strings"
 )
 
 type Io struct {
 	DispatchTable map[string]common.Sanitizer
 
-	scanner *bufio.Reader
-	printer *bufio.Writer
+	scanner  *bufio.Reader
+	printer  *bufio.Writer
+	eprinter *bufio.Writer
 }
 
-func New() *Io {
+// New creates the io module reading from stdin and
+// writing to stdout and stderr
+func New(stdin goio.Reader, stdout goio.Writer, stderr goio.Writer) *Io {
 	io := &Io{}
 	io.DispatchTable = map[string]common.Sanitizer{
-		"readln":  io.readlnSanitizer,
-		"print":   io.printSanitizer,
-		"println": io.printlnSanitizer,
+		"readln":   io.readlnSanitizer,
+		"print":    io.printSanitizer,
+		"println":  io.printlnSanitizer,
+		"eprint":   io.eprintSanitizer,
+		"eprintln": io.eprintlnSanitizer,
+		"printf":   io.printfSanitizer,
 	}
-	io.scanner = bufio.NewReader(os.Stdin)
-	io.printer = bufio.NewWriter(os.Stdout)
+	io.scanner = bufio.NewReader(stdin)
+	io.printer = bufio.NewWriter(stdout)
+	io.eprinter = bufio.NewWriter(stderr)
 	return io
 }
 
@@ -43,43 +53,69 @@ func (io *Io) readlnSanitizer(args ...any) (any, error) {
 	}
 
 	line, err := io.scanner.ReadString('\n')
-	line = line[:len(line)-1] // remove newline
-	return line, err
+	if errors.Is(err, goio.EOF) && line == "" {
+		// null marks the end of the input
+		return nil, nil
+	}
+	if err != nil && !errors.Is(err, goio.EOF) {
+		return nil, err
+	}
+
+	// remove newline
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return line, nil
 }
 
 func (io *Io) printSanitizer(args ...any) (any, error) {
-	var err error
-	for _, value := range args {
-		_, err = io.printer.WriteString(strings.From(value))
-		if err != nil {
-			return nil, err
-		}
+	return nil, write(io.printer, args, false)
+}
+
+func (io *Io) printlnSanitizer(args ...any) (any, error) {
+	return nil, write(io.printer, args, true)
+}
+
+func (io *Io) eprintSanitizer(args ...any) (any, error) {
+	return nil, write(io.eprinter, args, false)
+}
+
+func (io *Io) eprintlnSanitizer(args ...any) (any, error) {
+	return nil, write(io.eprinter, args, true)
+}
+
+// printf formats its arguments like `strings.format` and prints the result
+func (io *Io) printfSanitizer(args ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("printf expects at least one argument, got=0")
+	}
+	format, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("printf expects first argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
 	}
-	err = io.printer.Flush()
+
+	str, err := rostrings.Format(format, args[1:])
 	if err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	return nil, write(io.printer, []any{str}, false)
 }
 
-func (io *Io) printlnSanitizer(args ...any) (any, error) {
+func write(printer *bufio.Writer, args []any, newline bool) error {
 	var err error
 	for _, value := range args {
-		_, err = io.printer.WriteString(strings.From(value))
+		_, err = printer.WriteString(rostrings.From(value))
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
-	err = io.printer.WriteByte('\n')
-	if err != nil {
-		return nil, err
-	}
-
-	err = io.printer.Flush()
-	if err != nil {
-		return nil, err
+	if newline {
+		err = printer.WriteByte('\n')
+		if err != nil {
+			return err
+		}
 	}
 
-	return nil, nil
+	return printer.Flush()
 }
@@ -0,0 +1,293 @@
+package json
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+	rostrings "RoLang/stdlib/strings"
+
+	"bytes"
+	gojson "encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type Json struct {
+	DispatchTable map[string]common.Sanitizer
+}
+
+func New() *Json {
+	j := &Json{}
+	j.DispatchTable = map[string]common.Sanitizer{
+		"parse":     j.parseSanitizer,
+		"stringify": j.stringifySanitizer,
+	}
+
+	return j
+}
+
+func (j *Json) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := j.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in json module", name)
+	}
+
+	return sanitizer, nil
+}
+
+func (j *Json) parseSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("parse", args, 1); err != nil {
+		return nil, err
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("parse expects argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	return Parse(str)
+}
+
+// Parse decodes a json document into RoLang values
+func Parse(str string) (any, error) {
+	// the decoder's tokens report some mistakes late, so the
+	// document is checked as a whole first to locate them
+	var syntaxErr *gojson.SyntaxError
+	if err := gojson.Unmarshal([]byte(str), new(gojson.RawMessage)); errors.As(err, &syntaxErr) {
+		// the offset is past the offending character, or the
+		// length of the input when it ended too early
+		pos := syntaxErr.Offset
+		if !strings.HasPrefix(syntaxErr.Error(), "unexpected end") {
+			pos--
+		}
+		line := strings.Count(str[:pos], "\n") + 1
+		column := pos - int64(strings.LastIndex(str[:pos], "\n"))
+		return nil, fmt.Errorf("parse: %s at line %d, column %d", syntaxErr, line, column)
+	}
+
+	dec := gojson.NewDecoder(strings.NewReader(str))
+	dec.UseNumber()
+
+	return parseValue(dec)
+}
+
+func parseValue(dec *gojson.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := token.(type) {
+	case gojson.Delim:
+		switch v {
+		case '[':
+			arr := &objects.ArrayObject{}
+			for dec.More() {
+				elem, err := parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr.List = append(arr.List, elem)
+			}
+			_, err = dec.Token()
+			return arr, err
+		default:
+			// the decoder only hands out '[' and '{' here
+			obj := &objects.MapObject{}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.Set(key.(string), value)
+			}
+			_, err = dec.Token()
+			return obj, err
+		}
+	case gojson.Number:
+		return number(string(v))
+	default:
+		// strings, bools and null
+		return v, nil
+	}
+}
+
+func number(str string) (any, error) {
+	if !strings.ContainsAny(str, ".eE") {
+		if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return n, nil
+		}
+		n, _ := new(big.Int).SetString(str, 10)
+		return n, nil
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return nil, fmt.Errorf("number %s is out of range", str)
+	}
+
+	return f, nil
+}
+
+// stringify takes the value and an optional map of options, `indent`
+// is the number of spaces or the string to indent nested values with
+func (j *Json) stringifySanitizer(args ...any) (any, error) {
+	if err := common.ArgRange("stringify", args, 1, 2); err != nil {
+		return nil, err
+	}
+
+	indent := ""
+	if len(args) == 2 {
+		options, ok := args[1].(*objects.MapObject)
+		if !ok {
+			return nil, fmt.Errorf("stringify expects second argument to be map, got=%s",
+				builtin.TypeStr(args[1]))
+		}
+		for key, value := range options.All() {
+			if key != "indent" {
+				return nil, fmt.Errorf("stringify got unknown option %v", key)
+			}
+			switch v := value.(type) {
+			case int64:
+				if v < 0 || v > 16 {
+					return nil, fmt.Errorf("stringify expects indent between 0 and 16, got=%d", v)
+				}
+				indent = strings.Repeat(" ", int(v))
+			case string:
+				indent = v
+			default:
+				return nil, fmt.Errorf("stringify expects indent to be int or string, got=%s",
+					builtin.TypeStr(value))
+			}
+		}
+	}
+
+	return Stringify(args[0], indent)
+}
+
+// Stringify encodes a RoLang value as json, nested values are
+// put on their own lines and indented when indent is not empty
+func Stringify(value any, indent string) (string, error) {
+	var buf bytes.Buffer
+	enc := encoder{buf: &buf, visiting: map[any]bool{}}
+	if err := enc.encode(value); err != nil {
+		return "", fmt.Errorf("stringify: %w", err)
+	}
+	if indent == "" {
+		return buf.String(), nil
+	}
+
+	var out bytes.Buffer
+	if err := gojson.Indent(&out, buf.Bytes(), "", indent); err != nil {
+		return "", fmt.Errorf("stringify: %w", err)
+	}
+
+	return out.String(), nil
+}
+
+type encoder struct {
+	buf *bytes.Buffer
+	// the arrays and maps being encoded, to find cycles
+	visiting map[any]bool
+}
+
+func (enc *encoder) encode(value any) error {
+	switch v := value.(type) {
+	case nil:
+		enc.buf.WriteString("null")
+	case bool:
+		enc.buf.WriteString(strconv.FormatBool(v))
+	case int64:
+		enc.buf.WriteString(strconv.FormatInt(v, 10))
+	case *big.Int:
+		enc.buf.WriteString(v.String())
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("cannot encode %v, json has no such number", v)
+		}
+		enc.buf.WriteString(formatFloat(v))
+	case string:
+		enc.str(v)
+	case *objects.ArrayObject:
+		if enc.visiting[v] {
+			return errors.New("cannot encode an array containing itself")
+		}
+		enc.visiting[v] = true
+		defer delete(enc.visiting, v)
+
+		enc.buf.WriteByte('[')
+		for i, elem := range v.List {
+			if i > 0 {
+				enc.buf.WriteByte(',')
+			}
+			if err := enc.encode(elem); err != nil {
+				return err
+			}
+		}
+		enc.buf.WriteByte(']')
+	case *objects.MapObject:
+		if enc.visiting[v] {
+			return errors.New("cannot encode a map containing itself")
+		}
+		enc.visiting[v] = true
+		defer delete(enc.visiting, v)
+
+		enc.buf.WriteByte('{')
+		i := 0
+		for key, value := range v.All() {
+			str, ok := key.(string)
+			if !ok {
+				return fmt.Errorf("cannot encode map key %s of type %s, json keys must be strings",
+					rostrings.From(key), builtin.TypeStr(key))
+			}
+			if i > 0 {
+				enc.buf.WriteByte(',')
+			}
+			enc.str(str)
+			enc.buf.WriteByte(':')
+			if err := enc.encode(value); err != nil {
+				return err
+			}
+			i++
+		}
+		enc.buf.WriteByte('}')
+	default:
+		return fmt.Errorf("cannot encode value of type %s", builtin.TypeStr(v))
+	}
+
+	return nil
+}
+
+func (enc *encoder) str(str string) {
+	// html characters are kept as they are, the
+	// output is not meant to be embedded in a page
+	var buf bytes.Buffer
+	jsonEnc := gojson.NewEncoder(&buf)
+	jsonEnc.SetEscapeHTML(false)
+	jsonEnc.Encode(str)
+	enc.buf.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// floats keep a fraction or an exponent, so that
+// parsing them again gives back a float
+func formatFloat(f float64) string {
+	var str string
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		str = strconv.FormatFloat(f, 'e', -1, 64)
+	} else {
+		str = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+
+	return str
+}
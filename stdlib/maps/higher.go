@@ -0,0 +1,174 @@
+package maps
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+)
+
+func mapAndFunction(name string, args []any) (*objects.MapObject, any, error) {
+	if err := common.ArgCount(name, args, 2); err != nil {
+		return nil, nil, err
+	}
+
+	mp, ok := args[0].(*objects.MapObject)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s expects first argument to be map, got=%s",
+			name, builtin.TypeStr(args[0]))
+	}
+
+	if builtin.TypeStr(args[1]) != "function" {
+		return nil, nil, fmt.Errorf("%s expects second argument to be function, got=%s",
+			name, builtin.TypeStr(args[1]))
+	}
+
+	return mp, args[1], nil
+}
+
+// calls the function with the arguments it declares
+// parameters for, functions without a declaration get all
+func (m *Map) call(function any, args ...any) (any, error) {
+	if fn, ok := function.(objects.FuncObject); ok && len(fn.Function.Parameters) < len(args) {
+		args = args[:len(fn.Function.Parameters)]
+	}
+
+	return m.caller.Call(function, args...)
+}
+
+func (m *Map) mapValuesSanitizer(args ...any) (any, error) {
+	mp, fn, err := mapAndFunction("mapValues", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.MapObject{}
+	for _, entry := range entries(mp) {
+		value, err := m.call(fn, entry[1], entry[0])
+		if err != nil {
+			return nil, err
+		}
+		result.Set(entry[0], value)
+	}
+
+	return result, nil
+}
+
+func (m *Map) filterSanitizer(args ...any) (any, error) {
+	mp, fn, err := mapAndFunction("filter", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.MapObject{}
+	for _, entry := range entries(mp) {
+		keep, err := m.call(fn, entry[1], entry[0])
+		if err != nil {
+			return nil, err
+		}
+		if common.IsTruthy(keep) {
+			result.Set(entry[0], entry[1])
+		}
+	}
+
+	return result, nil
+}
+
+// merge combines maps into a new one. when the last argument is a
+// function it resolves keys found in more than one map, it receives
+// the value so far, the new value and the key and returns the value
+// to keep. without it the last value is kept, like with concat
+func (m *Map) mergeSanitizer(args ...any) (any, error) {
+	var resolver any
+	if len(args) > 0 && builtin.TypeStr(args[len(args)-1]) == "function" {
+		resolver = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("merge expects at least one map")
+	}
+
+	result := &objects.MapObject{}
+	for i, arg := range args {
+		mp, ok := arg.(*objects.MapObject)
+		if !ok {
+			return nil, fmt.Errorf("merge expects all arguments to be map, arg=%d got=%s",
+				i+1, builtin.TypeStr(arg))
+		}
+
+		for _, entry := range entries(mp) {
+			key, value := entry[0], entry[1]
+			if old, ok := result.Get(key); ok && resolver != nil {
+				var err error
+				if value, err = m.call(resolver, old, value, key); err != nil {
+					return nil, err
+				}
+			}
+			result.Set(key, value)
+		}
+	}
+
+	return result, nil
+}
+
+// deepMerge combines maps into a new one, merging the values of keys
+// which are maps in both instead of replacing them. the maps passed
+// are left unchanged
+func (m *Map) deepMergeSanitizer(args ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("deepMerge expects at least one argument")
+	}
+
+	result := &objects.MapObject{}
+	for i, arg := range args {
+		mp, ok := arg.(*objects.MapObject)
+		if !ok {
+			return nil, fmt.Errorf("deepMerge expects all arguments to be map, arg=%d got=%s",
+				i+1, builtin.TypeStr(arg))
+		}
+
+		var err error
+		if result, err = deepMerge(result, mp, map[*objects.MapObject]bool{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// merges src into a copy of dst, visiting holds the maps
+// being merged to stop at maps containing themselves
+func deepMerge(dst, src *objects.MapObject, visiting map[*objects.MapObject]bool) (*objects.MapObject, error) {
+	if visiting[src] {
+		return nil, fmt.Errorf("deepMerge cannot merge a map containing itself")
+	}
+	visiting[src] = true
+	defer delete(visiting, src)
+
+	result := dst.Clone()
+	for key, value := range src.All() {
+		old, ok := result.Get(key)
+		oldMap, oldIsMap := old.(*objects.MapObject)
+		newMap, newIsMap := value.(*objects.MapObject)
+		if ok && oldIsMap && newIsMap {
+			merged, err := deepMerge(oldMap, newMap, visiting)
+			if err != nil {
+				return nil, err
+			}
+			value = merged
+		}
+		result.Set(key, value)
+	}
+
+	return result, nil
+}
+
+func entries(mp *objects.MapObject) [][2]any {
+	list := make([][2]any, 0, mp.Len())
+	for key, value := range mp.All() {
+		list = append(list, [2]any{key, value})
+	}
+
+	return list
+}
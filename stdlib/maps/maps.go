@@ -4,23 +4,42 @@ import (
 	"RoLang/evaluator/objects"
 	"RoLang/stdlib/builtin"
 	"RoLang/stdlib/common"
-	"maps"
 
 	"fmt"
+	"slices"
 )
 
 type Map struct {
 	DispatchTable map[string]common.Sanitizer
+
+	caller common.Caller
 }
 
-func New() *Map {
-	m := &Map{}
+func New(caller common.Caller) *Map {
+	m := &Map{caller: caller}
 	m.DispatchTable = map[string]common.Sanitizer{
 		"len":    m.lenSanitizer,
 		"insert": m.insertSanitizer,
 		"erase":  m.eraseSanitizer,
 		"concat": m.concatSanitizer,
 		"copy":   m.copySanitizer,
+
+		"has":         m.hasSanitizer,
+		"get":         m.getSanitizer,
+		"set":         m.setSanitizer,
+		"keys":        m.keysSanitizer,
+		"values":      m.valuesSanitizer,
+		"entries":     m.entriesSanitizer,
+		"sortedKeys":  m.sortedKeysSanitizer,
+		"fromEntries": m.fromEntriesSanitizer,
+		"invert":      m.invertSanitizer,
+		"pick":        m.pickSanitizer,
+		"omit":        m.omitSanitizer,
+
+		"mapValues": m.mapValuesSanitizer,
+		"filter":    m.filterSanitizer,
+		"merge":     m.mergeSanitizer,
+		"deepMerge": m.deepMergeSanitizer,
 	}
 
 	return m
@@ -29,7 +48,7 @@ func New() *Map {
 func (m *Map) Dispatcher(name string) (common.Sanitizer, error) {
 	sanitizer, ok := m.DispatchTable[name]
 	if !ok {
-		return nil, fmt.Errorf("no method %q found in strings module", name)
+		return nil, fmt.Errorf("no method %q found in maps module", name)
 	}
 
 	return sanitizer, nil
@@ -59,6 +78,10 @@ func (m *Map) insertSanitizer(args ...any) (any, error) {
 			builtin.TypeStr(args[0]))
 	}
 
+	if err := builtin.CheckKey(args[1]); err != nil {
+		return nil, err
+	}
+
 	return mp.Insert(args[1], args[2]), nil
 }
 
@@ -73,11 +96,15 @@ func (m *Map) eraseSanitizer(args ...any) (any, error) {
 			builtin.TypeStr(args[0]))
 	}
 
+	if err := builtin.CheckKey(args[1]); err != nil {
+		return nil, err
+	}
+
 	return mp.Erase(args[1]), nil
 }
 
 func (m *Map) concatSanitizer(args ...any) (any, error) {
-	mps := &objects.MapObject{Map: make(map[any]any)}
+	mps := &objects.MapObject{}
 
 	for i, arg := range args {
 		mp, ok := arg.(*objects.MapObject)
@@ -102,13 +129,229 @@ func (m *Map) copySanitizer(args ...any) (any, error) {
 			builtin.TypeStr(args[0]))
 	}
 
-	mpCopy := &objects.MapObject{
-		Map: maps.Clone(mp.Map),
+	return mp.Clone(), nil
+}
+
+// Concat sets the entries of mp in mps, keys
+// already in mps keep their place
+func Concat(mps *objects.MapObject, mp *objects.MapObject) {
+	for key, value := range mp.All() {
+		mps.Set(key, value)
+	}
+}
+
+// checks the number of arguments and that the first one is a map
+func mapArgs(name string, args []any, count int) (*objects.MapObject, error) {
+	if err := common.ArgCount(name, args, count); err != nil {
+		return nil, err
+	}
+
+	mp, ok := args[0].(*objects.MapObject)
+	if !ok {
+		return nil, fmt.Errorf("%s expects %s to be map, got=%s",
+			name, common.Ordinal(0, count), builtin.TypeStr(args[0]))
 	}
 
-	return mpCopy, nil
+	return mp, nil
 }
 
-func Concat(mps *objects.MapObject, mp *objects.MapObject) {
-	maps.Copy(mps.Map, mp.Map)
+// checks that the argument is an array of values allowed as keys
+func keysArg(name string, arg any) ([]any, error) {
+	arr, ok := arg.(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("%s expects second argument to be array, got=%s",
+			name, builtin.TypeStr(arg))
+	}
+	for _, key := range arr.List {
+		if err := builtin.CheckKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return arr.List, nil
+}
+
+func (m *Map) hasSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("has", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := builtin.CheckKey(args[1]); err != nil {
+		return nil, err
+	}
+
+	_, ok := mp.Get(args[1])
+	return ok, nil
+}
+
+// get returns the value of a key, or the default value,
+// null unless one is passed, when the key is missing
+func (m *Map) getSanitizer(args ...any) (any, error) {
+	if err := common.ArgRange("get", args, 2, 3); err != nil {
+		return nil, err
+	}
+	mp, err := mapArgs("get", args[:2], 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := builtin.CheckKey(args[1]); err != nil {
+		return nil, err
+	}
+
+	value, ok := mp.Get(args[1])
+	if !ok && len(args) == 3 {
+		return args[2], nil
+	}
+
+	return value, nil
+}
+
+// set adds a key or replaces its value, like assigning with `[]` does
+func (m *Map) setSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("set", args, 3)
+	if err != nil {
+		return nil, err
+	}
+	if err := builtin.CheckKey(args[1]); err != nil {
+		return nil, err
+	}
+
+	mp.Set(args[1], args[2])
+	return nil, nil
+}
+
+// keys returns the keys in insertion order
+func (m *Map) keysSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("keys", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &objects.ArrayObject{List: mp.Keys()}, nil
+}
+
+// values returns the values in the order of their keys
+func (m *Map) valuesSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("values", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &objects.ArrayObject{List: mp.Values()}, nil
+}
+
+// entries returns [key, value] pairs in insertion order
+func (m *Map) entriesSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("entries", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]any, 0, mp.Len())
+	for key, value := range mp.All() {
+		entries = append(entries, &objects.ArrayObject{List: []any{key, value}})
+	}
+
+	return &objects.ArrayObject{List: entries}, nil
+}
+
+// sortedKeys returns the keys with bools first, then numbers
+// and then strings, each in ascending order
+func (m *Map) sortedKeysSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("sortedKeys", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := mp.Keys()
+	slices.SortStableFunc(keys, objects.CompareKeys)
+
+	return &objects.ArrayObject{List: keys}, nil
+}
+
+// fromEntries creates a map from an array of [key, value] pairs,
+// later pairs replace the values of earlier ones with the same key
+func (m *Map) fromEntriesSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("fromEntries", args, 1); err != nil {
+		return nil, err
+	}
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("fromEntries expects argument to be array, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	result := &objects.MapObject{}
+	for i, elem := range arr.List {
+		pair, ok := elem.(*objects.ArrayObject)
+		if !ok || len(pair.List) != 2 {
+			return nil, fmt.Errorf("fromEntries expects entries to be [key, value] pairs, entry=%d got=%s",
+				i, builtin.TypeStr(elem))
+		}
+		if err := builtin.CheckKey(pair.List[0]); err != nil {
+			return nil, err
+		}
+		result.Set(pair.List[0], pair.List[1])
+	}
+
+	return result, nil
+}
+
+// invert swaps keys and values, of keys with the
+// same value the last one becomes the value
+func (m *Map) invertSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("invert", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.MapObject{}
+	for key, value := range mp.All() {
+		if err := builtin.CheckKey(value); err != nil {
+			return nil, fmt.Errorf("invert: %w", err)
+		}
+		result.Set(value, key)
+	}
+
+	return result, nil
+}
+
+// pick returns a map with only the given keys, in the order they are given
+func (m *Map) pickSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("pick", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := keysArg("pick", args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.MapObject{}
+	for _, key := range keys {
+		if value, ok := mp.Get(key); ok {
+			result.Set(key, value)
+		}
+	}
+
+	return result, nil
+}
+
+// omit returns a map without the given keys
+func (m *Map) omitSanitizer(args ...any) (any, error) {
+	mp, err := mapArgs("omit", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := keysArg("omit", args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	result := mp.Clone()
+	for _, key := range keys {
+		result.Erase(key)
+	}
+
+	return result, nil
 }
@@ -0,0 +1,455 @@
+package math
+
+import (
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+	gomath "math"
+	"math/big"
+)
+
+type Math struct {
+	DispatchTable map[string]common.Sanitizer
+	Constants     map[string]any
+
+	ops common.Operators
+}
+
+// New creates the math module, ordering numbers with ops
+func New(ops common.Operators) *Math {
+	m := &Math{ops: ops}
+	m.DispatchTable = map[string]common.Sanitizer{
+		"abs":   m.absSanitizer,
+		"floor": rounding("floor", gomath.Floor),
+		"ceil":  rounding("ceil", gomath.Ceil),
+		"trunc": rounding("trunc", gomath.Trunc),
+		"round": m.roundSanitizer,
+		"sqrt":  unary("sqrt", gomath.Sqrt),
+		"cbrt":  unary("cbrt", gomath.Cbrt),
+		"pow":   m.powSanitizer,
+		"exp":   unary("exp", gomath.Exp),
+		"log":   m.logSanitizer,
+		"log2":  unary("log2", gomath.Log2),
+		"log10": unary("log10", gomath.Log10),
+		"sin":   unary("sin", gomath.Sin),
+		"cos":   unary("cos", gomath.Cos),
+		"tan":   unary("tan", gomath.Tan),
+		"asin":  unary("asin", gomath.Asin),
+		"acos":  unary("acos", gomath.Acos),
+		"atan":  unary("atan", gomath.Atan),
+		"atan2": m.atan2Sanitizer,
+		"hypot": m.hypotSanitizer,
+		"min":   m.minSanitizer,
+		"max":   m.maxSanitizer,
+		"clamp": m.clampSanitizer,
+		"gcd":   m.gcdSanitizer,
+		"lcm":   m.lcmSanitizer,
+		"isNaN": m.isNaNSanitizer,
+		"isInf": m.isInfSanitizer,
+	}
+	m.Constants = map[string]any{
+		"pi":       gomath.Pi,
+		"e":        gomath.E,
+		"inf":      gomath.Inf(1),
+		"nan":      gomath.NaN(),
+		"maxInt":   int64(gomath.MaxInt64),
+		"minInt":   int64(gomath.MinInt64),
+		"maxFloat": gomath.MaxFloat64,
+	}
+
+	return m
+}
+
+func (m *Math) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := m.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in math module", name)
+	}
+
+	return sanitizer, nil
+}
+
+func (m *Math) Constant(name string) (any, bool) {
+	value, ok := m.Constants[name]
+	return value, ok
+}
+
+// checks that the argument is an int or a float
+func number(name string, args []any, i int) error {
+	switch args[i].(type) {
+	case int64, *big.Int, float64:
+		return nil
+	}
+
+	return fmt.Errorf("%s expects %s to be number, got=%s",
+		name, common.Ordinal(i, len(args)), builtin.TypeStr(args[i]))
+}
+
+func toFloat(value any) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	default:
+		return value.(float64)
+	}
+}
+
+func toBig(value any) *big.Int {
+	if v, ok := value.(int64); ok {
+		return big.NewInt(v)
+	}
+
+	return value.(*big.Int)
+}
+
+func normalize(value *big.Int) any {
+	if value.IsInt64() {
+		return value.Int64()
+	}
+
+	return value
+}
+
+// float arguments of the given count, ints are converted
+func floats(name string, args []any, count int) ([]float64, error) {
+	if err := common.ArgCount(name, args, count); err != nil {
+		return nil, err
+	}
+
+	result := make([]float64, count)
+	for i, arg := range args {
+		if err := number(name, args, i); err != nil {
+			return nil, err
+		}
+		result[i] = toFloat(arg)
+	}
+
+	return result, nil
+}
+
+func unary(name string, fn func(float64) float64) common.Sanitizer {
+	return func(args ...any) (any, error) {
+		x, err := floats(name, args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return fn(x[0]), nil
+	}
+}
+
+// ints are already whole so they are returned unchanged
+func rounding(name string, fn func(float64) float64) common.Sanitizer {
+	return func(args ...any) (any, error) {
+		if err := common.ArgCount(name, args, 1); err != nil {
+			return nil, err
+		}
+		if err := number(name, args, 0); err != nil {
+			return nil, err
+		}
+
+		if f, ok := args[0].(float64); ok {
+			return fn(f), nil
+		}
+		return args[0], nil
+	}
+}
+
+func (m *Math) absSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("abs", args, 1); err != nil {
+		return nil, err
+	}
+	if err := number("abs", args, 0); err != nil {
+		return nil, err
+	}
+
+	switch v := args[0].(type) {
+	case float64:
+		return gomath.Abs(v), nil
+	case int64:
+		if v >= 0 {
+			return v, nil
+		}
+		return normalize(new(big.Int).Neg(big.NewInt(v))), nil
+	default:
+		return normalize(new(big.Int).Abs(toBig(v))), nil
+	}
+}
+
+// rounds half away from zero, to a number of decimal places when given
+func (m *Math) roundSanitizer(args ...any) (any, error) {
+	if err := common.ArgRange("round", args, 1, 2); err != nil {
+		return nil, err
+	}
+	if err := number("round", args, 0); err != nil {
+		return nil, err
+	}
+
+	places := int64(0)
+	if len(args) == 2 {
+		var ok bool
+		places, ok = args[1].(int64)
+		if !ok {
+			return nil, fmt.Errorf("round expects second argument to be int, got=%s",
+				builtin.TypeStr(args[1]))
+		}
+	}
+
+	f, ok := args[0].(float64)
+	if !ok || gomath.IsInf(f, 0) || gomath.IsNaN(f) {
+		return args[0], nil
+	}
+	if places == 0 {
+		return gomath.Round(f), nil
+	}
+
+	if places > 0 {
+		scale := gomath.Pow(10, float64(places))
+		scaled := f * scale
+		if gomath.IsInf(scale, 0) || gomath.IsInf(scaled, 0) {
+			// a float has no digits that far past the point
+			return f, nil
+		}
+		return gomath.Round(scaled) / scale, nil
+	}
+
+	scale := gomath.Pow(10, float64(-places))
+	if gomath.IsInf(scale, 0) {
+		// every float is nearer to 0 than to such a power of ten
+		return gomath.Copysign(0, f), nil
+	}
+	return gomath.Round(f/scale) * scale, nil
+}
+
+// largest number of bits an integer power may have
+const maxPowBits = 1 << 20
+
+func (m *Math) powSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("pow", args, 2); err != nil {
+		return nil, err
+	}
+	for i := range args {
+		if err := number("pow", args, i); err != nil {
+			return nil, err
+		}
+	}
+
+	_, floatBase := args[0].(float64)
+	exp, intExp := args[1].(int64)
+	// an int raised to a non-negative int is an exact int
+	if floatBase || !intExp || exp < 0 {
+		return gomath.Pow(toFloat(args[0]), toFloat(args[1])), nil
+	}
+
+	base := toBig(args[0])
+	if base.BitLen() > 1 && int64(base.BitLen()-1) > maxPowBits/max(exp, 1) {
+		return nil, fmt.Errorf("pow result is too large")
+	}
+
+	return normalize(new(big.Int).Exp(base, big.NewInt(exp), nil)), nil
+}
+
+// the natural logarithm, or the logarithm in the given base
+func (m *Math) logSanitizer(args ...any) (any, error) {
+	if err := common.ArgRange("log", args, 1, 2); err != nil {
+		return nil, err
+	}
+	if len(args) == 2 {
+		x, err := floats("log", args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return gomath.Log(x[0]) / gomath.Log(x[1]), nil
+	}
+
+	x, err := floats("log", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return gomath.Log(x[0]), nil
+}
+
+func (m *Math) atan2Sanitizer(args ...any) (any, error) {
+	x, err := floats("atan2", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return gomath.Atan2(x[0], x[1]), nil
+}
+
+func (m *Math) hypotSanitizer(args ...any) (any, error) {
+	x, err := floats("hypot", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return gomath.Hypot(x[0], x[1]), nil
+}
+
+func numbers(name string, args []any) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s expects at least one argument", name)
+	}
+	for i, arg := range args {
+		switch arg.(type) {
+		case int64, *big.Int, float64:
+		default:
+			return fmt.Errorf("%s expects all arguments to be number, arg=%d got=%s",
+				name, i+1, builtin.TypeStr(arg))
+		}
+	}
+
+	return nil
+}
+
+// min and max return the first of the smallest or largest
+// arguments unchanged, so ints are not turned into floats
+func (m *Math) extreme(name string, args []any, before func(l, r any) (bool, error)) (any, error) {
+	if err := numbers(name, args); err != nil {
+		return nil, err
+	}
+
+	result := args[0]
+	for _, arg := range args[1:] {
+		ok, err := before(arg, result)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = arg
+		}
+	}
+
+	return result, nil
+}
+
+func (m *Math) minSanitizer(args ...any) (any, error) {
+	return m.extreme("min", args, m.ops.Less)
+}
+
+func (m *Math) maxSanitizer(args ...any) (any, error) {
+	return m.extreme("max", args, func(l, r any) (bool, error) {
+		return m.ops.Less(r, l)
+	})
+}
+
+func (m *Math) clampSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("clamp", args, 3); err != nil {
+		return nil, err
+	}
+	for i := range args {
+		if err := number("clamp", args, i); err != nil {
+			return nil, err
+		}
+	}
+
+	value, lo, hi := args[0], args[1], args[2]
+	inverted, err := m.ops.Less(hi, lo)
+	if err != nil {
+		return nil, err
+	}
+	if inverted {
+		return nil, fmt.Errorf("clamp expects lower bound to not be greater than upper bound")
+	}
+
+	below, err := m.ops.Less(value, lo)
+	if err != nil {
+		return nil, err
+	}
+	if below {
+		return lo, nil
+	}
+
+	above, err := m.ops.Less(hi, value)
+	if err != nil {
+		return nil, err
+	}
+	if above {
+		return hi, nil
+	}
+
+	return value, nil
+}
+
+func integers(name string, args []any) ([]*big.Int, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s expects at least one argument", name)
+	}
+
+	result := make([]*big.Int, len(args))
+	for i, arg := range args {
+		switch arg.(type) {
+		case int64, *big.Int:
+			result[i] = new(big.Int).Abs(toBig(arg))
+		default:
+			return nil, fmt.Errorf("%s expects all arguments to be int, arg=%d got=%s",
+				name, i+1, builtin.TypeStr(arg))
+		}
+	}
+
+	return result, nil
+}
+
+// gcd and lcm are never negative, and gcd(0, 0) is 0
+func (m *Math) gcdSanitizer(args ...any) (any, error) {
+	ints, err := integers("gcd", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ints[0]
+	for _, n := range ints[1:] {
+		result = new(big.Int).GCD(nil, nil, result, n)
+	}
+
+	return normalize(result), nil
+}
+
+func (m *Math) lcmSanitizer(args ...any) (any, error) {
+	ints, err := integers("lcm", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ints[0]
+	for _, n := range ints[1:] {
+		if result.Sign() == 0 || n.Sign() == 0 {
+			result = new(big.Int)
+			continue
+		}
+		gcd := new(big.Int).GCD(nil, nil, result, n)
+		result = new(big.Int).Mul(new(big.Int).Quo(result, gcd), n)
+	}
+
+	return normalize(result), nil
+}
+
+func (m *Math) isNaNSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("isNaN", args, 1); err != nil {
+		return nil, err
+	}
+	if err := number("isNaN", args, 0); err != nil {
+		return nil, err
+	}
+
+	f, ok := args[0].(float64)
+	return ok && gomath.IsNaN(f), nil
+}
+
+func (m *Math) isInfSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("isInf", args, 1); err != nil {
+		return nil, err
+	}
+	if err := number("isInf", args, 0); err != nil {
+		return nil, err
+	}
+
+	f, ok := args[0].(float64)
+	return ok && gomath.IsInf(f, 0), nil
+}
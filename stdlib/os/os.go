@@ -0,0 +1,282 @@
+package os
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	goos "os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type Os struct {
+	DispatchTable map[string]common.Sanitizer
+
+	args    []string
+	env     map[string]string
+	access  common.FSAccess
+	context func() context.Context
+}
+
+// New creates the os module exposing args as `os.args`, confining
+// paths to access and stopping subprocesses once the context
+// returned by ctx is done
+func New(args []string, access common.FSAccess, ctx func() context.Context) *Os {
+	o := &Os{
+		args:    args,
+		env:     map[string]string{},
+		access:  access,
+		context: ctx,
+	}
+	o.DispatchTable = map[string]common.Sanitizer{
+		"getenv": o.getenvSanitizer,
+		"setenv": o.setenvSanitizer,
+		"exit":   o.exitSanitizer,
+		"cwd":    o.cwdSanitizer,
+		"exec":   o.execSanitizer,
+	}
+
+	for _, entry := range goos.Environ() {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			o.env[key] = value
+		}
+	}
+
+	return o
+}
+
+func (o *Os) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := o.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in os module", name)
+	}
+
+	return sanitizer, nil
+}
+
+// args is a fresh array on every read so scripts cannot change it for others
+func (o *Os) Constant(name string) (any, bool) {
+	if name != "args" {
+		return nil, false
+	}
+
+	args := &objects.ArrayObject{List: make([]any, len(o.args))}
+	for i, arg := range o.args {
+		args.List[i] = arg
+	}
+
+	return args, true
+}
+
+// getenv returns the value of a variable, or null if it is not set
+func (o *Os) getenvSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("getenv", args, 1); err != nil {
+		return nil, err
+	}
+	key, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("getenv expects argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	value, ok := o.env[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return value, nil
+}
+
+// setenv sets a variable, or unsets it when the value is null
+func (o *Os) setenvSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("setenv", args, 2); err != nil {
+		return nil, err
+	}
+	key, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("setenv expects first argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	if key == "" || strings.ContainsAny(key, "=\x00") {
+		return nil, fmt.Errorf("setenv: invalid variable name %q", key)
+	}
+
+	switch value := args[1].(type) {
+	case nil:
+		delete(o.env, key)
+	case string:
+		o.env[key] = value
+	default:
+		return nil, fmt.Errorf("setenv expects second argument to be string or null, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+
+	return nil, nil
+}
+
+// exit unwinds the whole script like a top level return does, so
+// the host's deferred cleanup runs before the process ends
+func (o *Os) exitSanitizer(args ...any) (any, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("exit expects at most one argument, got=%d", len(args))
+	}
+	if len(args) == 0 {
+		return nil, common.Exit{}
+	}
+
+	code, ok := args[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("exit expects argument to be int, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	if code < 0 || code > 255 {
+		return nil, fmt.Errorf("exit expects code to be between 0 and 255, got=%d", code)
+	}
+
+	return nil, common.Exit{Code: code}
+}
+
+// cwd is the directory relative paths are resolved against,
+// which is the root when scripts are confined to one
+func (o *Os) cwdSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("cwd", args, 0); err != nil {
+		return nil, err
+	}
+
+	if o.access.Root != "" {
+		return filepath.Abs(o.access.Root)
+	}
+
+	return goos.Getwd()
+}
+
+// exec runs a command with an array of arguments and a map of options:
+// `stdin` is a string fed to the command, `env` a map of variables added
+// to the environment and `dir` the directory it runs in. it returns a map
+// with the `stdout`, `stderr` and exit `code` of the command, a command
+// failing is not an error but one that cannot be started is
+func (o *Os) execSanitizer(args ...any) (any, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, fmt.Errorf("exec expects one to three arguments, got=%d", len(args))
+	}
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("exec expects first argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	var cmdArgs []string
+	if len(args) > 1 && args[1] != nil {
+		arr, ok := args[1].(*objects.ArrayObject)
+		if !ok {
+			return nil, fmt.Errorf("exec expects second argument to be array, got=%s",
+				builtin.TypeStr(args[1]))
+		}
+		for _, arg := range arr.List {
+			str, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("exec expects arguments to be strings, got=%s",
+					builtin.TypeStr(arg))
+			}
+			cmdArgs = append(cmdArgs, str)
+		}
+	}
+
+	cmd := exec.CommandContext(o.context(), name, cmdArgs...)
+	env := o.env
+	dir := "."
+
+	if len(args) > 2 {
+		options, ok := args[2].(*objects.MapObject)
+		if !ok {
+			return nil, fmt.Errorf("exec expects third argument to be map, got=%s",
+				builtin.TypeStr(args[2]))
+		}
+
+		for key, value := range options.All() {
+			switch key {
+			case "stdin":
+				stdin, ok := value.(string)
+				if !ok {
+					return nil, fmt.Errorf("exec expects stdin to be string, got=%s",
+						builtin.TypeStr(value))
+				}
+				cmd.Stdin = strings.NewReader(stdin)
+			case "dir":
+				if dir, ok = value.(string); !ok {
+					return nil, fmt.Errorf("exec expects dir to be string, got=%s",
+						builtin.TypeStr(value))
+				}
+			case "env":
+				vars, ok := value.(*objects.MapObject)
+				if !ok {
+					return nil, fmt.Errorf("exec expects env to be map, got=%s",
+						builtin.TypeStr(value))
+				}
+				env = make(map[string]string, len(o.env)+int(vars.Len()))
+				for key, value := range o.env {
+					env[key] = value
+				}
+				for key, value := range vars.All() {
+					k, ok1 := key.(string)
+					v, ok2 := value.(string)
+					if !ok1 || !ok2 {
+						return nil, fmt.Errorf("exec expects env to map strings to strings, got=%s: %s",
+							builtin.TypeStr(key), builtin.TypeStr(value))
+					}
+					env[k] = v
+				}
+			default:
+				return nil, fmt.Errorf("exec got unknown option %v", key)
+			}
+		}
+	}
+
+	resolved, err := o.access.Resolve("exec", dir, false)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = resolved
+
+	cmd.Env = make([]string, 0, len(env))
+	for key, value := range env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	slices.Sort(cmd.Env)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	code := int64(0)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() < 0 {
+			return nil, fmt.Errorf("exec %q: %w", name, unwrapExec(err))
+		}
+		code = int64(exitErr.ExitCode())
+	}
+
+	result := &objects.MapObject{}
+	result.Set("stdout", stdout.String())
+	result.Set("stderr", stderr.String())
+	result.Set("code", code)
+
+	return result, nil
+}
+
+// drops the command name exec.Error repeats
+func unwrapExec(err error) error {
+	var execErr *exec.Error
+	if errors.As(err, &execErr) {
+		return execErr.Err
+	}
+
+	return err
+}
@@ -0,0 +1,65 @@
+package stdlib
+
+import (
+	"RoLang/stdlib/common"
+
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Policy decides which modules and functions scripts may use.
+// Entries name either a whole module ("io") or a single
+// function of a module ("io.readln"), builtin functions are
+// named through the builtin module ("builtin.type").
+// The zero value permits everything
+type Policy struct {
+	// Allow lists what is permitted, when empty everything
+	// that is not denied is permitted
+	Allow []string
+	// Deny lists what is never permitted, it takes
+	// precedence over Allow
+	Deny []string
+	// Hide makes forbidden modules and functions look as if they
+	// did not exist, instead of failing when they are called
+	Hide bool
+	// FS restricts the paths modules touching the file system may use
+	FS common.FSAccess
+}
+
+// Permits reports whether the function of the module may be used
+func (p *Policy) Permits(module string, function string) bool {
+	name := module + "." + function
+
+	if slices.Contains(p.Deny, module) || slices.Contains(p.Deny, name) {
+		return false
+	}
+	// commands reach the file system without going through FS,
+	// so when it is restricted they must be allowed by name
+	if name == "os.exec" && p.FS != (common.FSAccess{}) && !slices.Contains(p.Allow, name) {
+		return false
+	}
+
+	return len(p.Allow) == 0 || slices.Contains(p.Allow, module) || slices.Contains(p.Allow, name)
+}
+
+// visible reports whether anything of the module can be used,
+// so that a hidden policy can hide whole modules
+func (p *Policy) visible(module string) bool {
+	if slices.Contains(p.Deny, module) {
+		return false
+	}
+	if len(p.Allow) == 0 || slices.Contains(p.Allow, module) {
+		return true
+	}
+
+	return slices.ContainsFunc(p.Allow, func(name string) bool {
+		return strings.HasPrefix(name, module+".")
+	})
+}
+
+func denied(module string, function string) common.Sanitizer {
+	return func(...any) (any, error) {
+		return nil, fmt.Errorf("permission denied: %s.%s", module, function)
+	}
+}
@@ -0,0 +1,262 @@
+package random
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+type Random struct {
+	DispatchTable map[string]common.Sanitizer
+
+	pcg    *rand.PCG
+	rand   *rand.Rand
+	secure *rand.Rand
+}
+
+// New creates the random module, seeded with seed when it is not nil
+// and with a random seed otherwise
+func New(seed *int64) *Random {
+	r := &Random{}
+	r.DispatchTable = map[string]common.Sanitizer{
+		"seed":    r.seedSanitizer,
+		"int":     r.intSanitizer(false),
+		"float":   r.floatSanitizer(false),
+		"choice":  r.choiceSanitizer(false),
+		"shuffle": r.shuffleSanitizer,
+		"sample":  r.sampleSanitizer,
+
+		"secureInt":    r.intSanitizer(true),
+		"secureFloat":  r.floatSanitizer(true),
+		"secureChoice": r.choiceSanitizer(true),
+		"secureToken":  r.secureTokenSanitizer,
+	}
+
+	if seed != nil {
+		r.pcg = rand.NewPCG(uint64(*seed), uint64(*seed))
+	} else {
+		r.pcg = rand.NewPCG(rand.Uint64(), rand.Uint64())
+	}
+	r.rand = rand.New(r.pcg)
+	r.secure = rand.New(cryptoSource{})
+
+	return r
+}
+
+func (r *Random) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := r.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in random module", name)
+	}
+
+	return sanitizer, nil
+}
+
+// cryptoSource reads from the operating system's secure generator
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		panic(fmt.Errorf("reading secure random numbers: %w", err))
+	}
+
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+func (r *Random) generator(secure bool) *rand.Rand {
+	if secure {
+		return r.secure
+	}
+
+	return r.rand
+}
+
+func (r *Random) seedSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("seed", args, 1); err != nil {
+		return nil, err
+	}
+	seed, ok := args[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("seed expects argument to be int, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	r.pcg.Seed(uint64(seed), uint64(seed))
+	return nil, nil
+}
+
+// int returns an int between lo and hi, both included
+func (r *Random) intSanitizer(secure bool) common.Sanitizer {
+	name := "int"
+	if secure {
+		name = "secureInt"
+	}
+
+	return func(args ...any) (any, error) {
+		if err := common.ArgCount(name, args, 2); err != nil {
+			return nil, err
+		}
+		lo, ok := args[0].(int64)
+		if !ok {
+			return nil, fmt.Errorf("%s expects first argument to be int, got=%s",
+				name, builtin.TypeStr(args[0]))
+		}
+		hi, ok := args[1].(int64)
+		if !ok {
+			return nil, fmt.Errorf("%s expects second argument to be int, got=%s",
+				name, builtin.TypeStr(args[1]))
+		}
+		if lo > hi {
+			return nil, fmt.Errorf("%s expects lower bound to not be greater than upper bound", name)
+		}
+
+		gen := r.generator(secure)
+		span := uint64(hi) - uint64(lo)
+		if span == math.MaxUint64 {
+			return int64(gen.Uint64()), nil
+		}
+
+		return lo + int64(gen.Uint64N(span+1)), nil
+	}
+}
+
+// float returns a float in [0, 1), or in [lo, hi) when given bounds
+func (r *Random) floatSanitizer(secure bool) common.Sanitizer {
+	name := "float"
+	if secure {
+		name = "secureFloat"
+	}
+
+	return func(args ...any) (any, error) {
+		if len(args) != 0 && len(args) != 2 {
+			return nil, fmt.Errorf("%s expects zero or two arguments, got=%d", name, len(args))
+		}
+
+		f := r.generator(secure).Float64()
+		if len(args) == 0 {
+			return f, nil
+		}
+
+		bounds := make([]float64, 2)
+		for i, arg := range args {
+			switch v := arg.(type) {
+			case int64:
+				bounds[i] = float64(v)
+			case float64:
+				bounds[i] = v
+			default:
+				return nil, fmt.Errorf("%s expects bounds to be numbers, got=%s",
+					name, builtin.TypeStr(arg))
+			}
+		}
+		if bounds[0] > bounds[1] {
+			return nil, fmt.Errorf("%s expects lower bound to not be greater than upper bound", name)
+		}
+
+		return bounds[0] + f*(bounds[1]-bounds[0]), nil
+	}
+}
+
+func arrayArg(name string, args []any, count int) (*objects.ArrayObject, error) {
+	if err := common.ArgCount(name, args, count); err != nil {
+		return nil, err
+	}
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("%s expects %s to be array, got=%s",
+			name, common.Ordinal(0, count), builtin.TypeStr(args[0]))
+	}
+
+	return arr, nil
+}
+
+func (r *Random) choiceSanitizer(secure bool) common.Sanitizer {
+	name := "choice"
+	if secure {
+		name = "secureChoice"
+	}
+
+	return func(args ...any) (any, error) {
+		arr, err := arrayArg(name, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(arr.List) == 0 {
+			return nil, fmt.Errorf("%s from an empty array", name)
+		}
+
+		return arr.List[r.generator(secure).IntN(len(arr.List))], nil
+	}
+}
+
+// returns a shuffled copy of the array
+func (r *Random) shuffleSanitizer(args ...any) (any, error) {
+	arr, err := arrayArg("shuffle", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{List: slices.Clone(arr.List)}
+	r.rand.Shuffle(len(result.List), func(i, j int) {
+		result.List[i], result.List[j] = result.List[j], result.List[i]
+	})
+
+	return result, nil
+}
+
+// picks k elements at distinct positions, in random order
+func (r *Random) sampleSanitizer(args ...any) (any, error) {
+	arr, err := arrayArg("sample", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	k, ok := args[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("sample expects second argument to be int, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+	if k < 0 || k > int64(len(arr.List)) {
+		return nil, fmt.Errorf("sample size %d is out of range for array of length %d",
+			k, len(arr.List))
+	}
+
+	// a partial Fisher-Yates shuffle of a copy
+	list := slices.Clone(arr.List)
+	for i := range int(k) {
+		j := i + r.rand.IntN(len(list)-i)
+		list[i], list[j] = list[j], list[i]
+	}
+
+	return &objects.ArrayObject{List: list[:k:k]}, nil
+}
+
+// secureToken returns n secure random bytes as a hex string
+func (r *Random) secureTokenSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("secureToken", args, 1); err != nil {
+		return nil, err
+	}
+	n, ok := args[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("secureToken expects argument to be int, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	if n <= 0 || n > 1<<16 {
+		return nil, fmt.Errorf("secureToken expects a size between 1 and %d, got=%d", 1<<16, n)
+	}
+
+	buf := make([]byte, n)
+	if _, err := crand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
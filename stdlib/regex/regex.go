@@ -0,0 +1,330 @@
+package regex
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+type Regex struct {
+	DispatchTable map[string]common.Sanitizer
+
+	caller common.Caller
+}
+
+func New(caller common.Caller) *Regex {
+	r := &Regex{caller: caller}
+	r.DispatchTable = map[string]common.Sanitizer{
+		"compile":  r.compileSanitizer,
+		"match":    r.matchSanitizer,
+		"find":     r.findSanitizer,
+		"findAll":  r.findAllSanitizer,
+		"captures": r.capturesSanitizer,
+		"replace":  r.replaceSanitizer,
+		"split":    r.splitSanitizer,
+	}
+
+	return r
+}
+
+func (r *Regex) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := r.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in regex module", name)
+	}
+
+	return sanitizer, nil
+}
+
+// Compile compiles a pattern, syntax errors tell the
+// column of the pattern at which they were found
+func Compile(pattern string) (*objects.RegexObject, error) {
+	re, err := regexp.Compile(pattern)
+	if err == nil {
+		return &objects.RegexObject{Regexp: re}, nil
+	}
+
+	var syntaxErr *syntax.Error
+	if !errors.As(err, &syntaxErr) {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+
+	return nil, fmt.Errorf("compile: %s at column %d of %q",
+		syntaxErr.Code, column(pattern, syntaxErr), pattern)
+}
+
+// finds where in the pattern a syntax error is, counting
+// code points from 1 like the locations of the lexer
+func column(pattern string, err *syntax.Error) int {
+	switch err.Code {
+	case syntax.ErrTrailingBackslash:
+		// the backslash at the end has nothing to escape
+		return runeColumn(pattern, len(pattern)-1)
+	case syntax.ErrMissingParen:
+		// the closing ) is missing at the end
+		return runeColumn(pattern, len(pattern))
+	case syntax.ErrUnexpectedParen:
+		if i := unexpectedParen(pattern); i >= 0 {
+			return runeColumn(pattern, i)
+		}
+	}
+
+	if i := strings.Index(pattern, err.Expr); i >= 0 {
+		return runeColumn(pattern, i)
+	}
+
+	return 1
+}
+
+// the column of the byte at index i
+func runeColumn(pattern string, i int) int {
+	return utf8.RuneCountInString(pattern[:i]) + 1
+}
+
+// returns the index of the first ) without an open group, parentheses
+// in character classes like [)] are plain characters
+func unexpectedParen(pattern string) int {
+	depth := 0
+	// where the items of the open class start, -1 outside of classes
+	class := -1
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\':
+			i++
+		case class >= 0:
+			if strings.HasPrefix(pattern[i:], "[:") {
+				// named classes like [:alpha:] end with :]
+				if end := strings.Index(pattern[i+2:], ":]"); end >= 0 {
+					i += end + 3
+				}
+			} else if pattern[i] == ']' && i > class {
+				// a ] right at the start is a plain character
+				class = -1
+			}
+		case pattern[i] == '[':
+			class = i + 1
+			if class < len(pattern) && pattern[class] == '^' {
+				class++
+			}
+		case pattern[i] == '(':
+			depth++
+		case pattern[i] == ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
+
+// checks the number of arguments, that the first one is a regex or a
+// pattern and the second one a string, the rest are left to the caller
+func regexArgs(name string, args []any, least, most int) (*regexp.Regexp, string, error) {
+	if err := common.ArgRange(name, args, least, most); err != nil {
+		return nil, "", err
+	}
+
+	var re *regexp.Regexp
+	switch v := args[0].(type) {
+	case *objects.RegexObject:
+		re = v.Regexp
+	case string:
+		compiled, err := Compile(v)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", name, err)
+		}
+		re = compiled.Regexp
+	default:
+		return nil, "", fmt.Errorf("%s expects first argument to be regex or string, got=%s",
+			name, builtin.TypeStr(args[0]))
+	}
+
+	str, ok := args[1].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("%s expects second argument to be string, got=%s",
+			name, builtin.TypeStr(args[1]))
+	}
+
+	return re, str, nil
+}
+
+// the optional limit of findAll and split, -1 when it is not given
+func limitArg(name string, args []any) (int, error) {
+	if len(args) < 3 {
+		return -1, nil
+	}
+
+	n, ok := args[2].(int64)
+	if !ok {
+		return 0, fmt.Errorf("%s expects third argument to be int, got=%s",
+			name, builtin.TypeStr(args[2]))
+	}
+
+	return int(n), nil
+}
+
+func (r *Regex) compileSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("compile", args, 1); err != nil {
+		return nil, err
+	}
+	pattern, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("compile expects argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	return Compile(pattern)
+}
+
+// match reports whether the pattern matches anywhere in the string
+func (r *Regex) matchSanitizer(args ...any) (any, error) {
+	re, str, err := regexArgs("match", args, 2, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return re.MatchString(str), nil
+}
+
+// find returns the first match, or null when there is none
+func (r *Regex) findSanitizer(args ...any) (any, error) {
+	re, str, err := regexArgs("find", args, 2, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := re.FindStringIndex(str)
+	if loc == nil {
+		return nil, nil
+	}
+
+	return str[loc[0]:loc[1]], nil
+}
+
+// findAll returns every match, or at most n of them when n is given
+func (r *Regex) findAllSanitizer(args ...any) (any, error) {
+	re, str, err := regexArgs("findAll", args, 2, 3)
+	if err != nil {
+		return nil, err
+	}
+	n, err := limitArg("findAll", args)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := &objects.ArrayObject{List: []any{}}
+	for _, match := range re.FindAllString(str, n) {
+		matches.List = append(matches.List, match)
+	}
+
+	return matches, nil
+}
+
+// groups returns the groups of a match as a map, by their number with
+// 0 being the whole match, and named groups by their name as well.
+// groups which took no part in the match are null
+func groups(re *regexp.Regexp, str string, loc []int) *objects.MapObject {
+	result := &objects.MapObject{}
+	names := re.SubexpNames()
+	for i := range len(loc) / 2 {
+		var value any
+		if loc[2*i] >= 0 {
+			value = str[loc[2*i]:loc[2*i+1]]
+		}
+		result.Set(int64(i), value)
+		if names[i] != "" {
+			result.Set(names[i], value)
+		}
+	}
+
+	return result
+}
+
+// captures returns the groups of the first match, or null when there is none
+func (r *Regex) capturesSanitizer(args ...any) (any, error) {
+	re, str, err := regexArgs("captures", args, 2, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := re.FindStringSubmatchIndex(str)
+	if loc == nil {
+		return nil, nil
+	}
+
+	return groups(re, str, loc), nil
+}
+
+// replace replaces every match. a string replacement can refer to groups
+// with $1 or ${name}, a function is called with the match, and the map of
+// groups too if it is a RoLang function declaring two parameters, and
+// returns the replacement
+func (r *Regex) replaceSanitizer(args ...any) (any, error) {
+	re, str, err := regexArgs("replace", args, 3, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	if template, ok := args[2].(string); ok {
+		return re.ReplaceAllString(str, template), nil
+	}
+	if builtin.TypeStr(args[2]) != "function" {
+		return nil, fmt.Errorf("replace expects third argument to be string or function, got=%s",
+			builtin.TypeStr(args[2]))
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(str, -1) {
+		callArgs := []any{str[loc[0]:loc[1]]}
+		// stdlib functions like strings.upper only get the match
+		if fn, ok := args[2].(objects.FuncObject); ok && len(fn.Function.Parameters) > 1 {
+			callArgs = append(callArgs, groups(re, str, loc))
+		}
+
+		value, err := r.caller.Call(args[2], callArgs...)
+		if err != nil {
+			return nil, err
+		}
+		replacement, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("replace expects function to return string, got=%s",
+				builtin.TypeStr(value))
+		}
+
+		out.WriteString(str[last:loc[0]])
+		out.WriteString(replacement)
+		last = loc[1]
+	}
+	out.WriteString(str[last:])
+
+	return out.String(), nil
+}
+
+// split splits the string around the matches, into at most n parts when n is given
+func (r *Regex) splitSanitizer(args ...any) (any, error) {
+	re, str, err := regexArgs("split", args, 2, 3)
+	if err != nil {
+		return nil, err
+	}
+	n, err := limitArg("split", args)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := &objects.ArrayObject{List: []any{}}
+	for _, part := range re.Split(str, n) {
+		parts.List = append(parts.List, part)
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,180 @@
+package sets
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+)
+
+type Sets struct {
+	DispatchTable map[string]common.Sanitizer
+}
+
+func New() *Sets {
+	s := &Sets{}
+	s.DispatchTable = map[string]common.Sanitizer{
+		"new":          s.newSanitizer,
+		"len":          s.lenSanitizer,
+		"add":          s.addSanitizer,
+		"remove":       s.removeSanitizer,
+		"has":          s.hasSanitizer,
+		"toArray":      s.toArraySanitizer,
+		"union":        s.operationSanitizer("union", (*objects.SetObject).Union),
+		"intersection": s.operationSanitizer("intersection", (*objects.SetObject).Intersection),
+		"difference":   s.operationSanitizer("difference", (*objects.SetObject).Difference),
+		"isSubset":     s.isSubsetSanitizer,
+	}
+
+	return s
+}
+
+func (s *Sets) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := s.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in sets module", name)
+	}
+
+	return sanitizer, nil
+}
+
+// checks the number of arguments and that the first one is a set
+func setArgs(name string, args []any, count int) (*objects.SetObject, error) {
+	if err := common.ArgCount(name, args, count); err != nil {
+		return nil, err
+	}
+
+	set, ok := args[0].(*objects.SetObject)
+	if !ok {
+		return nil, fmt.Errorf("%s expects %s to be set, got=%s",
+			name, common.Ordinal(0, count), builtin.TypeStr(args[0]))
+	}
+
+	return set, nil
+}
+
+// new creates an empty set, or a set of the elements of an array
+func (s *Sets) newSanitizer(args ...any) (any, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("new expects at most one argument, got=%d", len(args))
+	}
+
+	set := &objects.SetObject{}
+	if len(args) == 0 {
+		return set, nil
+	}
+
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("new expects argument to be array, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	for _, elem := range arr.List {
+		if err := builtin.CheckKey(elem); err != nil {
+			return nil, err
+		}
+		set.Add(elem)
+	}
+
+	return set, nil
+}
+
+func (s *Sets) lenSanitizer(args ...any) (any, error) {
+	set, err := setArgs("len", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return set.Len(), nil
+}
+
+// add returns false if the element was already in the set
+func (s *Sets) addSanitizer(args ...any) (any, error) {
+	set, err := setArgs("add", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := builtin.CheckKey(args[1]); err != nil {
+		return nil, err
+	}
+
+	return set.Add(args[1]), nil
+}
+
+// remove returns false if the element was not in the set
+func (s *Sets) removeSanitizer(args ...any) (any, error) {
+	set, err := setArgs("remove", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := builtin.CheckKey(args[1]); err != nil {
+		return nil, err
+	}
+
+	return set.Remove(args[1]), nil
+}
+
+func (s *Sets) hasSanitizer(args ...any) (any, error) {
+	set, err := setArgs("has", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := builtin.CheckKey(args[1]); err != nil {
+		return nil, err
+	}
+
+	return set.Has(args[1]), nil
+}
+
+// toArray returns the elements in insertion order, for
+// use with the functions of the arrays module
+func (s *Sets) toArraySanitizer(args ...any) (any, error) {
+	set, err := setArgs("toArray", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &objects.ArrayObject{List: set.List()}, nil
+}
+
+// the set operations take one or more sets and apply the operation
+// from left to right, returning a new set
+func (s *Sets) operationSanitizer(name string,
+	operation func(*objects.SetObject, *objects.SetObject) *objects.SetObject) common.Sanitizer {
+	return func(args ...any) (any, error) {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%s expects at least one argument", name)
+		}
+
+		var result *objects.SetObject
+		for i, arg := range args {
+			set, ok := arg.(*objects.SetObject)
+			if !ok {
+				return nil, fmt.Errorf("%s expects all arguments to be set, arg=%d got=%s",
+					name, i+1, builtin.TypeStr(arg))
+			}
+			if result == nil {
+				result = (&objects.SetObject{}).Union(set)
+			} else {
+				result = operation(result, set)
+			}
+		}
+
+		return result, nil
+	}
+}
+
+func (s *Sets) isSubsetSanitizer(args ...any) (any, error) {
+	set, err := setArgs("isSubset", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	other, ok := args[1].(*objects.SetObject)
+	if !ok {
+		return nil, fmt.Errorf("isSubset expects second argument to be set, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+
+	return set.IsSubset(other), nil
+}
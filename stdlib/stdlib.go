@@ -4,38 +4,137 @@ import (
 	"RoLang/stdlib/arrays"
 	"RoLang/stdlib/builtin"
 	"RoLang/stdlib/common"
+	"RoLang/stdlib/deques"
+	"RoLang/stdlib/fs"
+	"RoLang/stdlib/heaps"
 	"RoLang/stdlib/io"
+	"RoLang/stdlib/json"
 	"RoLang/stdlib/maps"
+	"RoLang/stdlib/math"
+	"RoLang/stdlib/os"
+	"RoLang/stdlib/random"
+	"RoLang/stdlib/regex"
+	"RoLang/stdlib/sets"
 	"RoLang/stdlib/strings"
+	"RoLang/stdlib/unicode"
 
+	"context"
 	"fmt"
+	goio "io"
+	goos "os"
 )
 
 type Module interface {
 	Dispatcher(string) (common.Sanitizer, error)
 }
 
+// ConstantModule is implemented by modules which also
+// expose values, like `math.pi`, besides functions
+type ConstantModule interface {
+	Module
+	Constant(string) (any, bool)
+}
+
 type StdLib struct {
-	Modules map[string]Module
+	Modules   map[string]Module
+	Policy    Policy
+	Caller    common.Caller
+	Operators common.Operators
+}
+
+// Config describes the environment the modules are set up for
+type Config struct {
+	Policy Policy
+	// Caller lets functions taking callbacks call RoLang functions
+	Caller common.Caller
+	// Operators gives functions RoLang's meaning of equality, order and addition
+	Operators common.Operators
+	// streams used by the io module, the process' standard
+	// streams are used for the ones left nil
+	Stdin  goio.Reader
+	Stdout goio.Writer
+	Stderr goio.Writer
+	// Seed makes the random module deterministic,
+	// a random seed is used when it is nil
+	Seed *int64
+	// Args are the command line arguments exposed as `os.args`
+	Args []string
+	// Context returns the context scripts currently run under,
+	// subprocesses are stopped once it is done
+	Context func() context.Context
 }
 
-func New() *StdLib {
+func New(config Config) *StdLib {
+	if config.Stdin == nil {
+		config.Stdin = goos.Stdin
+	}
+	if config.Stdout == nil {
+		config.Stdout = goos.Stdout
+	}
+	if config.Stderr == nil {
+		config.Stderr = goos.Stderr
+	}
+	if config.Context == nil {
+		config.Context = context.Background
+	}
+
 	return &StdLib{
+		Policy:    config.Policy,
+		Caller:    config.Caller,
+		Operators: config.Operators,
 		Modules: map[string]Module{
-			"arrays":  arrays.New(),
-			"builtin": builtin.New(),
-			"io":      io.New(),
-			"maps":    maps.New(),
+			"arrays":  arrays.New(config.Caller, config.Operators),
+			"builtin": builtin.New(strings.From, config.Operators),
+			"deques":  deques.New(),
+			"fs":      fs.New(config.Policy.FS),
+			"heaps":   heaps.New(config.Caller, config.Operators),
+			"io":      io.New(config.Stdin, config.Stdout, config.Stderr),
+			"json":    json.New(),
+			"maps":    maps.New(config.Caller),
+			"math":    math.New(config.Operators),
+			"os":      os.New(config.Args, config.Policy.FS, config.Context),
+			"random":  random.New(config.Seed),
+			"regex":   regex.New(config.Caller),
+			"sets":    sets.New(),
 			"strings": strings.New(),
+			"unicode": unicode.New(),
 		},
 	}
 }
 
 func (s *StdLib) GetModuleDispatcher(name string, method string) (common.Sanitizer, error) {
 	module, ok := s.Modules[name]
-	if !ok {
+	if !ok || s.Policy.Hide && !s.Policy.visible(name) {
 		return nil, fmt.Errorf("no module named %q", name)
 	}
 
-	return module.Dispatcher(method)
+	sanitizer, err := module.Dispatcher(method)
+	if err != nil || s.Policy.Permits(name, method) {
+		return sanitizer, err
+	}
+
+	if s.Policy.Hide {
+		return nil, fmt.Errorf("no method %q found in %s module", method, name)
+	}
+
+	return denied(name, method), nil
+}
+
+// GetMember resolves `module.name` to a constant of the
+// module if it has one, or else to one of its functions
+func (s *StdLib) GetMember(name string, member string) (any, error) {
+	module, ok := s.Modules[name].(ConstantModule)
+	if !ok || s.Policy.Hide && !s.Policy.Permits(name, member) {
+		return s.GetModuleDispatcher(name, member)
+	}
+
+	value, ok := module.Constant(member)
+	if !ok {
+		return s.GetModuleDispatcher(name, member)
+	}
+	if !s.Policy.Permits(name, member) {
+		return nil, fmt.Errorf("permission denied: %s.%s", name, member)
+	}
+
+	return value, nil
 }
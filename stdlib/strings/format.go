@@ -0,0 +1,304 @@
+package strings
+
+import (
+	"RoLang/stdlib/builtin"
+
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type formatSpec struct {
+	fill      rune
+	align     rune
+	sign      rune
+	alternate bool
+	width     int
+	precision int
+	verb      rune
+}
+
+func (s *String) formatSanitizer(args ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("format expects at least one argument, got=0")
+	}
+	format, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("format expects first argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	return Format(format, args[1:])
+}
+
+// Format replaces the placeholders of format with the formatted args
+func Format(format string, args []any) (string, error) {
+	var out strings.Builder
+	next, manual := 0, false
+
+	for i := 0; i < len(format); i++ {
+		char := format[i]
+		if char == '}' {
+			if i+1 < len(format) && format[i+1] == '}' {
+				out.WriteByte('}')
+				i++
+				continue
+			}
+			return "", fmt.Errorf("single '}' in format string at %d", i)
+		}
+		if char != '{' {
+			out.WriteByte(char)
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '{' {
+			out.WriteByte('{')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(format[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unclosed placeholder in format string at %d", i)
+		}
+		field := format[i+1 : i+end]
+		i += end
+
+		name, specStr, _ := strings.Cut(field, ":")
+		index := next
+		if name == "" {
+			if manual {
+				return "", fmt.Errorf("cannot mix automatic and numbered placeholders in format string")
+			}
+			next++
+		} else {
+			n, err := strconv.Atoi(name)
+			if err != nil || n < 0 {
+				return "", fmt.Errorf("invalid placeholder {%s} in format string", field)
+			}
+			if next > 0 {
+				return "", fmt.Errorf("cannot mix automatic and numbered placeholders in format string")
+			}
+			index, manual = n, true
+		}
+		if index >= len(args) {
+			return "", fmt.Errorf("format expects at least %d values, got=%d", index+1, len(args))
+		}
+
+		spec, err := parseSpec(specStr)
+		if err != nil {
+			return "", err
+		}
+		str, err := spec.apply(args[index])
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(str)
+	}
+
+	return out.String(), nil
+}
+
+func parseSpec(str string) (*formatSpec, error) {
+	spec := &formatSpec{fill: ' ', precision: -1}
+	invalid := fmt.Errorf("invalid format spec %q", str)
+
+	chars := []rune(str)
+	isAlign := func(char rune) bool {
+		return strings.ContainsRune("<>^=", char)
+	}
+	switch {
+	case len(chars) >= 2 && isAlign(chars[1]):
+		spec.fill, spec.align, chars = chars[0], chars[1], chars[2:]
+	case len(chars) >= 1 && isAlign(chars[0]):
+		spec.align, chars = chars[0], chars[1:]
+	}
+
+	if len(chars) > 0 && strings.ContainsRune("+- ", chars[0]) {
+		spec.sign, chars = chars[0], chars[1:]
+	}
+	if len(chars) > 0 && chars[0] == '#' {
+		spec.alternate, chars = true, chars[1:]
+	}
+	if len(chars) > 0 && chars[0] == '0' {
+		if spec.align == 0 {
+			spec.fill, spec.align = '0', '='
+		}
+		chars = chars[1:]
+	}
+
+	digits := func() (int, bool) {
+		n := 0
+		for len(chars) > 0 && chars[0] >= '0' && chars[0] <= '9' {
+			n = n*10 + int(chars[0]-'0')
+			chars = chars[1:]
+			if n > 1<<20 {
+				return 0, false
+			}
+		}
+		return n, true
+	}
+
+	var ok bool
+	if spec.width, ok = digits(); !ok {
+		return nil, invalid
+	}
+	if len(chars) > 0 && chars[0] == '.' {
+		chars = chars[1:]
+		if len(chars) == 0 || chars[0] < '0' || chars[0] > '9' {
+			return nil, invalid
+		}
+		if spec.precision, ok = digits(); !ok {
+			return nil, invalid
+		}
+	}
+
+	if len(chars) == 1 && strings.ContainsRune("dboxXfeEg%s", chars[0]) {
+		spec.verb, chars = chars[0], chars[1:]
+	}
+	if len(chars) != 0 {
+		return nil, invalid
+	}
+
+	return spec, nil
+}
+
+func (spec *formatSpec) apply(value any) (string, error) {
+	numeric := false
+	var body, sign string
+
+	switch spec.verb {
+	case 'd', 'b', 'o', 'x', 'X':
+		var n *big.Int
+		switch v := value.(type) {
+		case int64:
+			n = big.NewInt(v)
+		case *big.Int:
+			n = v
+		default:
+			return "", fmt.Errorf("format verb '%c' expects int, got=%s",
+				spec.verb, builtin.TypeStr(value))
+		}
+
+		base := map[rune]int{'d': 10, 'b': 2, 'o': 8, 'x': 16, 'X': 16}[spec.verb]
+		body = new(big.Int).Abs(n).Text(base)
+		if spec.alternate && base != 10 {
+			body = "0" + string(map[int]rune{2: 'b', 8: 'o', 16: 'x'}[base]) + body
+		}
+		if spec.verb == 'X' {
+			body = strings.ToUpper(body)
+		}
+		if n.Sign() < 0 {
+			sign = "-"
+		}
+		numeric = true
+	case 'f', 'e', 'E', 'g', '%':
+		var f float64
+		switch v := value.(type) {
+		case int64:
+			f = float64(v)
+		case *big.Int:
+			f, _ = new(big.Float).SetInt(v).Float64()
+		case float64:
+			f = v
+		default:
+			return "", fmt.Errorf("format verb '%c' expects a number, got=%s",
+				spec.verb, builtin.TypeStr(value))
+		}
+		body, sign = spec.float(f)
+		numeric = true
+	default:
+		switch v := value.(type) {
+		case int64, *big.Int:
+			body = From(v)
+			if body[0] == '-' {
+				body, sign = body[1:], "-"
+			}
+			numeric = true
+		case float64:
+			if spec.precision >= 0 {
+				// a precision means a fixed number of decimals
+				spec.verb = 'f'
+				body, sign = spec.float(v)
+			} else {
+				body = From(v)
+				if body[0] == '-' {
+					body, sign = body[1:], "-"
+				}
+			}
+			numeric = true
+		default:
+			body = From(value)
+			if spec.precision >= 0 && Len(body) > int64(spec.precision) {
+				body = string([]rune(body)[:spec.precision])
+			}
+		}
+	}
+
+	if numeric {
+		if sign == "" && (spec.sign == '+' || spec.sign == ' ') {
+			sign = string(spec.sign)
+		}
+	} else if spec.sign != 0 || spec.alternate {
+		return "", fmt.Errorf("sign and '#' are not allowed with %s values", builtin.TypeStr(value))
+	}
+
+	align := spec.align
+	if align == 0 {
+		align = '<'
+		if numeric {
+			align = '>'
+		}
+	}
+	if align == '=' && !numeric {
+		return "", fmt.Errorf("'=' alignment is not allowed with %s values", builtin.TypeStr(value))
+	}
+
+	missing := spec.width - utf8.RuneCountInString(sign+body)
+	if missing <= 0 {
+		return sign + body, nil
+	}
+	fill := strings.Repeat(string(spec.fill), missing)
+
+	switch align {
+	case '<':
+		return sign + body + fill, nil
+	case '>':
+		return fill + sign + body, nil
+	case '^':
+		half := strings.Repeat(string(spec.fill), missing/2)
+		return half + sign + body + strings.Repeat(string(spec.fill), missing-missing/2), nil
+	default:
+		return sign + fill + body, nil
+	}
+}
+
+// formats a float for the `f`, `e`, `E`, `g` and `%` verbs,
+// returning the digits and the sign separately
+func (spec *formatSpec) float(f float64) (string, string) {
+	precision := spec.precision
+	verb := byte(spec.verb)
+	suffix := ""
+
+	switch verb {
+	case '%':
+		f *= 100
+		verb, suffix = 'f', "%"
+		fallthrough
+	case 'f', 'e', 'E':
+		if precision < 0 {
+			precision = 6
+		}
+	}
+
+	body := strconv.FormatFloat(f, verb, precision, 64) + suffix
+	if body[0] == '-' {
+		return body[1:], "-"
+	}
+	if body[0] == '+' {
+		return body[1:], ""
+	}
+
+	return body, ""
+}
@@ -6,8 +6,11 @@ import (
 	"RoLang/stdlib/common"
 
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type String struct {
@@ -23,6 +26,29 @@ func New() *String {
 		"trimSpace":  s.trimSpaceSanitizer,
 		"split":      s.splitSanitizer,
 		"splitSpace": s.splitSpaceSanitizer,
+		"slice":      s.sliceSanitizer,
+
+		"contains":    s.containsSanitizer,
+		"startsWith":  s.startsWithSanitizer,
+		"endsWith":    s.endsWithSanitizer,
+		"indexOf":     s.indexOfSanitizer,
+		"lastIndexOf": s.lastIndexOfSanitizer,
+		"replace":     s.replaceSanitizer,
+		"replaceAll":  s.replaceAllSanitizer,
+		"upper":       s.upperSanitizer,
+		"lower":       s.lowerSanitizer,
+		"title":       s.titleSanitizer,
+		"repeat":      s.repeatSanitizer,
+		"join":        s.joinSanitizer,
+		"padLeft":     s.padLeftSanitizer,
+		"padRight":    s.padRightSanitizer,
+		"reverse":     s.reverseSanitizer,
+		"chars":       s.charsSanitizer,
+		"lines":       s.linesSanitizer,
+		"count":       s.countSanitizer,
+		"trimLeft":    s.trimLeftSanitizer,
+		"trimRight":   s.trimRightSanitizer,
+		"format":      s.formatSanitizer,
 	}
 	return s
 }
@@ -56,22 +82,57 @@ func (s *String) lenSanitizer(args ...any) (any, error) {
 	return Len(str), nil
 }
 
+// trim, trimLeft and trimRight remove the characters in the cutset
+// from the ends of the string, or whitespace when there is no cutset
+func trimArgs(name string, args []any) (string, string, bool, error) {
+	if err := common.ArgRange(name, args, 1, 2); err != nil {
+		return "", "", false, err
+	}
+	strs, err := stringArgs(name, args, 2)
+	if err != nil {
+		return "", "", false, err
+	}
+	if len(strs) == 1 {
+		return strs[0], "", false, nil
+	}
+
+	return strs[0], strs[1], true, nil
+}
+
 func (s *String) trimSanitizer(args ...any) (any, error) {
-	if len(args) != 1 {
-		return nil, fmt.Errorf("trim expects one argument, got=%d", len(args))
+	str, cut, ok, err := trimArgs("trim", args)
+	if err != nil {
+		return nil, err
 	}
-	str, ok := args[0].(string)
 	if !ok {
-		return nil, fmt.Errorf("trim expects first argument to be string, got=%s",
-			builtin.TypeStr(args[0]))
+		return strings.TrimSpace(str), nil
+	}
+
+	return Trim(str, cut), nil
+}
+
+func (s *String) trimLeftSanitizer(args ...any) (any, error) {
+	str, cut, ok, err := trimArgs("trimLeft", args)
+	if err != nil {
+		return nil, err
 	}
-	cut, ok := args[1].(string)
 	if !ok {
-		return nil, fmt.Errorf("trim expects second argument to be string, got=%s",
-			builtin.TypeStr(args[1]))
+		return strings.TrimLeftFunc(str, unicode.IsSpace), nil
 	}
 
-	return Trim(str, cut), nil
+	return strings.TrimLeft(str, cut), nil
+}
+
+func (s *String) trimRightSanitizer(args ...any) (any, error) {
+	str, cut, ok, err := trimArgs("trimRight", args)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return strings.TrimRightFunc(str, unicode.IsSpace), nil
+	}
+
+	return strings.TrimRight(str, cut), nil
 }
 
 func (s *String) trimSpaceSanitizer(args ...any) (any, error) {
@@ -84,7 +145,7 @@ func (s *String) trimSpaceSanitizer(args ...any) (any, error) {
 			builtin.TypeStr(args[0]))
 	}
 
-	return Trim(str, " "), nil
+	return strings.TrimSpace(str), nil
 }
 
 func (s *String) splitSanitizer(args ...any) (any, error) {
@@ -118,11 +179,38 @@ func (s *String) splitSpaceSanitizer(args ...any) (any, error) {
 	return SplitSpace(str), nil
 }
 
+func (s *String) sliceSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("slice", args, 3); err != nil {
+		return nil, err
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("slice expects first argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	start, ok := args[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("slice expects second argument to be int, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+	end, ok := args[2].(int64)
+	if !ok {
+		return nil, fmt.Errorf("slice expects third argument to be int, got=%s",
+			builtin.TypeStr(args[2]))
+	}
+
+	return Slice(str, start, end)
+}
+
 func From(value any) string {
 	var out string
 	switch v := value.(type) {
 	case int64:
 		out += strconv.FormatInt(v, 10)
+	case *big.Int:
+		out += v.String()
+	case objects.BigIntKey:
+		out += string(v)
 	case float64:
 		out += strconv.FormatFloat(v, 'f', -1, 64)
 	case string:
@@ -132,7 +220,7 @@ func From(value any) string {
 	case *objects.MapObject:
 		out += "{"
 		i := 0
-		for k, v := range v.Map {
+		for k, v := range v.All() {
 			key := From(k)
 			val := From(v)
 			elem := key + ": " + val
@@ -154,10 +242,21 @@ func From(value any) string {
 			}
 		}
 		out += "]"
-	case *objects.FuncObject:
+	case *objects.SetObject:
+		out += "set" + From(&objects.ArrayObject{List: v.List()})
+	case *objects.DequeObject:
+		out += "deque" + From(&objects.ArrayObject{List: v.List()})
+	case *objects.HeapObject:
+		// the top of the heap comes first, the rest in no particular order
+		out += "heap" + From(&objects.ArrayObject{List: v.List})
+	case objects.FuncObject:
 		out += "function"
-	case *common.Sanitizer:
+	case common.Sanitizer:
 		out += "function"
+	case *objects.FileObject:
+		out += "<file " + strconv.Quote(v.Path) + ">"
+	case *objects.RegexObject:
+		out += "<regex " + strconv.Quote(v.Regexp.String()) + ">"
 	case nil:
 		out += "null"
 	default:
@@ -167,12 +266,41 @@ func From(value any) string {
 	return out
 }
 
+// Len returns the number of code points in the string
 func Len(str string) int64 {
-	return int64(len(str))
+	return int64(utf8.RuneCountInString(str))
+}
+
+// At returns the code point at index as a string
+func At(str string, index int64) (string, bool) {
+	if index < 0 {
+		return "", false
+	}
+
+	for _, char := range str {
+		if index == 0 {
+			return string(char), true
+		}
+		index--
+	}
+
+	return "", false
+}
+
+// Slice returns the code points in the range [start, end)
+func Slice(str string, start int64, end int64) (string, error) {
+	chars := []rune(str)
+	if start < 0 || end < start || end > int64(len(chars)) {
+		return "", fmt.Errorf("slice bounds out of range [%d:%d] with length %d",
+			start, end, len(chars))
+	}
+
+	return string(chars[start:end]), nil
 }
 
+// Trim removes every leading and trailing code point contained in cut
 func Trim(str string, cut string) string {
-	return strings.TrimSpace(str)
+	return strings.Trim(str, cut)
 }
 
 func Split(str string, sep string) *objects.ArrayObject {
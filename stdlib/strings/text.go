@@ -0,0 +1,333 @@
+package strings
+
+import (
+	"RoLang/evaluator/objects"
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// checks that every argument is a string, count is the
+// number of arguments the function takes at most
+func stringArgs(name string, args []any, count int) ([]string, error) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s expects %s to be string, got=%s",
+				name, common.Ordinal(i, count), builtin.TypeStr(arg))
+		}
+		strs[i] = str
+	}
+
+	return strs, nil
+}
+
+func exactStrings(name string, args []any, count int) ([]string, error) {
+	if err := common.ArgCount(name, args, count); err != nil {
+		return nil, err
+	}
+
+	return stringArgs(name, args, count)
+}
+
+func (s *String) containsSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("contains", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Contains(strs[0], strs[1]), nil
+}
+
+func (s *String) startsWithSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("startsWith", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.HasPrefix(strs[0], strs[1]), nil
+}
+
+func (s *String) endsWithSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("endsWith", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.HasSuffix(strs[0], strs[1]), nil
+}
+
+// IndexOf returns the code point index of the first occurrence of sub or -1
+func IndexOf(str string, sub string) int64 {
+	index := strings.Index(str, sub)
+	if index < 0 {
+		return -1
+	}
+
+	return Len(str[:index])
+}
+
+// LastIndexOf returns the code point index of the last occurrence of sub or -1
+func LastIndexOf(str string, sub string) int64 {
+	index := strings.LastIndex(str, sub)
+	if index < 0 {
+		return -1
+	}
+
+	return Len(str[:index])
+}
+
+func (s *String) indexOfSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("indexOf", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return IndexOf(strs[0], strs[1]), nil
+}
+
+func (s *String) lastIndexOfSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("lastIndexOf", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return LastIndexOf(strs[0], strs[1]), nil
+}
+
+func (s *String) replaceSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("replace", args, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Replace(strs[0], strs[1], strs[2], 1), nil
+}
+
+func (s *String) replaceAllSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("replaceAll", args, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.ReplaceAll(strs[0], strs[1], strs[2]), nil
+}
+
+func (s *String) upperSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("upper", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return cases.Upper(language.Und).String(strs[0]), nil
+}
+
+func (s *String) lowerSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("lower", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return cases.Lower(language.Und).String(strs[0]), nil
+}
+
+// case mappings use the full unicode rules, so "ß" upper cases to "SS".
+// title upper cases the first letter of every word and lower cases the rest
+func (s *String) titleSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("title", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return cases.Title(language.Und).String(strs[0]), nil
+}
+
+func (s *String) repeatSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("repeat", args, 2); err != nil {
+		return nil, err
+	}
+	strs, err := stringArgs("repeat", args[:1], 2)
+	if err != nil {
+		return nil, err
+	}
+	count, ok := args[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("repeat expects second argument to be int, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("repeat expects a non-negative count, got=%d", count)
+	}
+	if len(strs[0]) > 0 && count > math.MaxInt32/int64(len(strs[0])) {
+		return nil, fmt.Errorf("repeat count %d is too large", count)
+	}
+
+	return strings.Repeat(strs[0], int(count)), nil
+}
+
+// joins the elements of an array, converting them like `strings.from`
+func (s *String) joinSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("join", args, 2); err != nil {
+		return nil, err
+	}
+	arr, ok := args[0].(*objects.ArrayObject)
+	if !ok {
+		return nil, fmt.Errorf("join expects first argument to be array, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	sep, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("join expects second argument to be string, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+
+	elems := make([]string, len(arr.List))
+	for i, elem := range arr.List {
+		elems[i] = From(elem)
+	}
+
+	return strings.Join(elems, sep), nil
+}
+
+// Pad fills str up to width code points with repetitions of pad, on the
+// left or on the right. the last repetition is cut short when needed
+func Pad(str string, width int64, pad string, left bool) string {
+	missing := width - Len(str)
+	if missing <= 0 || pad == "" {
+		return str
+	}
+
+	fill := make([]rune, 0, missing)
+	for len(fill) < int(missing) {
+		for _, char := range pad {
+			if len(fill) == int(missing) {
+				break
+			}
+			fill = append(fill, char)
+		}
+	}
+
+	if left {
+		return string(fill) + str
+	}
+	return str + string(fill)
+}
+
+func padArgs(name string, args []any) (string, int64, string, error) {
+	if err := common.ArgRange(name, args, 2, 3); err != nil {
+		return "", 0, "", err
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		return "", 0, "", fmt.Errorf("%s expects first argument to be string, got=%s",
+			name, builtin.TypeStr(args[0]))
+	}
+	width, ok := args[1].(int64)
+	if !ok {
+		return "", 0, "", fmt.Errorf("%s expects second argument to be int, got=%s",
+			name, builtin.TypeStr(args[1]))
+	}
+	if width > math.MaxInt32 {
+		return "", 0, "", fmt.Errorf("%s width %d is too large", name, width)
+	}
+
+	pad := " "
+	if len(args) == 3 {
+		pad, ok = args[2].(string)
+		if !ok {
+			return "", 0, "", fmt.Errorf("%s expects third argument to be string, got=%s",
+				name, builtin.TypeStr(args[2]))
+		}
+		if pad == "" {
+			return "", 0, "", fmt.Errorf("%s expects a non-empty padding", name)
+		}
+	}
+
+	return str, width, pad, nil
+}
+
+func (s *String) padLeftSanitizer(args ...any) (any, error) {
+	str, width, pad, err := padArgs("padLeft", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return Pad(str, width, pad, true), nil
+}
+
+func (s *String) padRightSanitizer(args ...any) (any, error) {
+	str, width, pad, err := padArgs("padRight", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return Pad(str, width, pad, false), nil
+}
+
+func (s *String) reverseSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("reverse", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	chars := []rune(strs[0])
+	slices.Reverse(chars)
+
+	return string(chars), nil
+}
+
+func (s *String) charsSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("chars", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{List: make([]any, 0, utf8.RuneCountInString(strs[0]))}
+	for _, char := range strs[0] {
+		result.List = append(result.List, string(char))
+	}
+
+	return result, nil
+}
+
+// splits on "\n" and "\r\n". a trailing line break does
+// not start another line, so "a\nb\n" has two lines
+func (s *String) linesSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("lines", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &objects.ArrayObject{}
+	str := strings.TrimSuffix(strs[0], "\n")
+	if strs[0] == "" {
+		return result, nil
+	}
+	for _, line := range strings.Split(str, "\n") {
+		result.List = append(result.List, strings.TrimSuffix(line, "\r"))
+	}
+
+	return result, nil
+}
+
+// counts the non-overlapping occurrences of a substring
+func (s *String) countSanitizer(args ...any) (any, error) {
+	strs, err := exactStrings("count", args, 2)
+	if err != nil {
+		return nil, err
+	}
+	if strs[1] == "" {
+		return nil, fmt.Errorf("count expects a non-empty substring")
+	}
+
+	return int64(strings.Count(strs[0], strs[1])), nil
+}
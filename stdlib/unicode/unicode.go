@@ -0,0 +1,154 @@
+package unicode
+
+import (
+	"RoLang/stdlib/builtin"
+	"RoLang/stdlib/common"
+
+	"fmt"
+	"slices"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type Unicode struct {
+	DispatchTable map[string]common.Sanitizer
+}
+
+func New() *Unicode {
+	u := &Unicode{}
+	u.DispatchTable = map[string]common.Sanitizer{
+		"isLetter":  predicate("isLetter", unicode.IsLetter),
+		"isDigit":   predicate("isDigit", unicode.IsDigit),
+		"isNumber":  predicate("isNumber", unicode.IsNumber),
+		"isSpace":   predicate("isSpace", unicode.IsSpace),
+		"isUpper":   predicate("isUpper", unicode.IsUpper),
+		"isLower":   predicate("isLower", unicode.IsLower),
+		"isTitle":   predicate("isTitle", unicode.IsTitle),
+		"isPunct":   predicate("isPunct", unicode.IsPunct),
+		"isSymbol":  predicate("isSymbol", unicode.IsSymbol),
+		"isMark":    predicate("isMark", unicode.IsMark),
+		"isControl": predicate("isControl", unicode.IsControl),
+		"isPrint":   predicate("isPrint", unicode.IsPrint),
+		"isGraphic": predicate("isGraphic", unicode.IsGraphic),
+		"category":  u.categorySanitizer,
+		"normalize": u.normalizeSanitizer,
+	}
+
+	return u
+}
+
+func (u *Unicode) Dispatcher(name string) (common.Sanitizer, error) {
+	sanitizer, ok := u.DispatchTable[name]
+	if !ok {
+		return nil, fmt.Errorf("no method %q found in unicode module", name)
+	}
+
+	return sanitizer, nil
+}
+
+// predicate builds a sanitizer that reports whether a string
+// is non empty and every code point in it satisfies `test`
+func predicate(name string, test func(rune) bool) common.Sanitizer {
+	return func(args ...any) (any, error) {
+		if err := common.ArgCount(name, args, 1); err != nil {
+			return nil, err
+		}
+		str, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s expects argument to be string, got=%s",
+				name, builtin.TypeStr(args[0]))
+		}
+
+		if len(str) == 0 {
+			return false, nil
+		}
+		for _, char := range str {
+			if !test(char) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
+
+// the two letter general categories, sorted so that the result
+// does not depend on the order of map iteration
+var categories = func() []string {
+	names := []string{}
+	for name := range unicode.Categories {
+		// LC is an alias for the union of Lu, Ll and Lt
+		if len(name) == 2 && name != "LC" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}()
+
+func (u *Unicode) categorySanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("category", args, 1); err != nil {
+		return nil, err
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("category expects argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+
+	chars := []rune(str)
+	if len(chars) != 1 {
+		return nil, fmt.Errorf("category expects a single character, got %d characters",
+			len(chars))
+	}
+
+	return Category(chars[0]), nil
+}
+
+func (u *Unicode) normalizeSanitizer(args ...any) (any, error) {
+	if err := common.ArgCount("normalize", args, 2); err != nil {
+		return nil, err
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("normalize expects first argument to be string, got=%s",
+			builtin.TypeStr(args[0]))
+	}
+	form, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("normalize expects second argument to be string, got=%s",
+			builtin.TypeStr(args[1]))
+	}
+
+	return Normalize(str, form)
+}
+
+// Category returns the two letter general category of a
+// code point such as "Lu" or "Nd", or "Cn" if unassigned
+func Category(char rune) string {
+	for _, name := range categories {
+		if unicode.Is(unicode.Categories[name], char) {
+			return name
+		}
+	}
+
+	return "Cn"
+}
+
+// Normalize converts a string to one of the forms NFC, NFD, NFKC or NFKD
+func Normalize(str string, form string) (string, error) {
+	switch form {
+	case "NFC":
+		return norm.NFC.String(str), nil
+	case "NFD":
+		return norm.NFD.String(str), nil
+	case "NFKC":
+		return norm.NFKC.String(str), nil
+	case "NFKD":
+		return norm.NFKD.String(str), nil
+	default:
+		return "", fmt.Errorf("unknown normalization form %q, expect NFC, NFD, NFKC or NFKD", form)
+	}
+}
@@ -22,12 +22,16 @@ const (
 	SLASH  // "/"
 	LT     // "<"
 	GT     // ">"
+	PIPE   // "|"
+	AMP    // "&"
 
 	EQ // "=="
 	NE // "!="
 	LE // "<="
 	GE // ">="
 
+	ARROW // "->"
+
 	// Delimeters
 	COLON  // ":"
 	COMMA  // ","
@@ -53,6 +57,7 @@ const (
 	NULL   // "null"
 	BREAK  // "break"
 	CONT   // "continue"
+	IN     // "in"
 
 	TOTAL // total number of tokens
 )
@@ -63,6 +68,7 @@ var TokenString = []string{
 	IDENT:  "identifier",
 	INT:    "integer",
 	FLOAT:  "float",
+	STRING: "string",
 	DOT:    ".",
 	ASSIGN: "=",
 	PLUS:   "+",
@@ -76,12 +82,17 @@ var TokenString = []string{
 	NE:     "!=",
 	LE:     "<=",
 	GE:     ">=",
+	PIPE:   "|",
+	AMP:    "&",
+	ARROW:  "->",
 	COMMA:  ",",
 	SEMCOL: ";",
 	LPAREN: "(",
 	RPAREN: ")",
 	LBRACE: "{",
 	RBRACE: "}",
+	LBRACK: "[",
+	RBRACK: "]",
 	FN:     "fn",
 	RETURN: "return",
 	LET:    "let",
@@ -94,6 +105,7 @@ var TokenString = []string{
 	NULL:   "null",
 	BREAK:  "break",
 	CONT:   "continue",
+	IN:     "in",
 }
 
 type Token struct {
@@ -114,6 +126,7 @@ var keywords = map[string]TokenType{
 	"null":     NULL,
 	"break":    BREAK,
 	"continue": CONT,
+	"in":       IN,
 }
 
 func LookUpKeyword(word string) TokenType {
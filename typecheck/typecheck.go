@@ -0,0 +1,552 @@
+package typecheck
+
+import (
+	"RoLang/ast"
+	"RoLang/token"
+
+	"fmt"
+)
+
+// symbol is a variable known to the checker, variables without an
+// annotation take the type of their initialiser and widen on assignment
+type symbol struct {
+	typ      Type
+	declared bool
+}
+
+type scope struct {
+	symbols map[string]*symbol
+	outer   *scope
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{
+		symbols: make(map[string]*symbol),
+		outer:   outer,
+	}
+}
+
+func (s *scope) lookup(name string) (*symbol, bool) {
+	sym, ok := s.symbols[name]
+	if !ok && s.outer != nil {
+		return s.outer.lookup(name)
+	}
+
+	return sym, ok
+}
+
+type Checker struct {
+	errors []error
+	scope  *scope
+	// return type of the function being checked, nil at top level
+	returns Type
+	// types inferred for expressions, keyed by node
+	types map[ast.Expression]Type
+}
+
+func New() *Checker {
+	return &Checker{
+		scope: newScope(nil),
+		types: make(map[ast.Expression]Type),
+	}
+}
+
+// Check walks the program and reports every type mismatch it can prove
+// statically. Unannotated code is assumed to be correct wherever its type
+// cannot be inferred, so a dynamically typed program checks cleanly
+func (c *Checker) Check(program *ast.Program) []error {
+	c.errors = nil
+	c.checkStatements(program.Statements)
+
+	return c.errors
+}
+
+// TypeOf returns the type inferred for an expression during the last check
+func (c *Checker) TypeOf(expr ast.Expression) Type {
+	if typ, ok := c.types[expr]; ok {
+		return typ
+	}
+
+	return Any{}
+}
+
+func (c *Checker) checkStatements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		c.checkStatement(stmt)
+	}
+}
+
+func (c *Checker) checkStatement(statement ast.Statement) {
+	switch stmt := statement.(type) {
+	case *ast.LetStatement:
+		c.checkLetStatement(stmt)
+	case *ast.FunctionStatement:
+		c.checkFunctionStatement(stmt)
+	case *ast.ReturnStatement:
+		c.checkReturnStatement(stmt)
+	case *ast.IfStatement:
+		c.checkCondition(stmt.Condition)
+		c.checkBlock(stmt.Then)
+		if stmt.Else != nil {
+			c.checkStatement(stmt.Else)
+		}
+	case *ast.BlockStatement:
+		c.checkBlock(stmt)
+	case *ast.ExpressionStatement:
+		c.checkExpression(stmt.Expression)
+	case *ast.LoopStatement:
+		if stmt.Condition != nil {
+			c.checkCondition(stmt.Condition)
+		}
+		c.checkBlock(stmt.Body)
+	}
+}
+
+func (c *Checker) checkBlock(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+
+	c.scope = newScope(c.scope)
+	c.checkStatements(block.Statements)
+	c.scope = c.scope.outer
+}
+
+// conditions accept any value since every value has a truthiness
+func (c *Checker) checkCondition(expr ast.Expression) {
+	c.checkExpression(expr)
+}
+
+func (c *Checker) checkLetStatement(let *ast.LetStatement) {
+	init := c.checkExpression(let.InitValue)
+
+	sym := &symbol{typ: init}
+	if let.Type != nil {
+		declared := FromAST(let.Type)
+		if !Assignable(declared, init) {
+			c.report(let.Location(), "cannot use %s as type %s in let %s",
+				init, declared, let.Ident.Value)
+		}
+		sym = &symbol{typ: declared, declared: true}
+	}
+
+	c.declare(let.Location(), let.Ident.Value, sym)
+}
+
+func (c *Checker) checkFunctionStatement(function *ast.FunctionStatement) {
+	// declared before checking the body so that it can recurse
+	c.declare(function.Location(), function.Ident.Value, &symbol{
+		typ:      c.signature(function.Value),
+		declared: true,
+	})
+
+	c.checkFunctionBody(function.Value)
+}
+
+func (c *Checker) checkReturnStatement(ret *ast.ReturnStatement) {
+	typ := Type(Null)
+	if ret.ReturnValue != nil {
+		typ = c.checkExpression(ret.ReturnValue)
+	}
+
+	if c.returns == nil {
+		// top level return is the exit code of the process
+		if !Assignable(NewUnion(Int, Null), typ) {
+			c.report(ret.Location(), "can only return integer exit codes at top level, got %s", typ)
+		}
+		return
+	}
+
+	if !Assignable(c.returns, typ) {
+		c.report(ret.Location(), "cannot return %s from function returning %s", typ, c.returns)
+	}
+}
+
+func (c *Checker) checkExpression(expression ast.Expression) Type {
+	var typ Type
+
+	switch expr := expression.(type) {
+	case nil:
+		typ = Any{}
+	case *ast.IntegerLiteral:
+		typ = Int
+	case *ast.FloatLiteral:
+		typ = Float
+	case *ast.StringLiteral:
+		typ = String
+	case *ast.BoolLiteral:
+		typ = Bool
+	case *ast.NullLiteral:
+		typ = Null
+	case *ast.Identifier:
+		typ = Any{}
+		if sym, ok := c.scope.lookup(expr.Value); ok {
+			typ = sym.typ
+		}
+	case *ast.ArrayLiteral:
+		elems := []Type{}
+		for _, elem := range expr.Elements {
+			elems = append(elems, c.checkExpression(elem))
+		}
+		typ = &Array{Elem: NewUnion(elems...)}
+	case *ast.MapLiteral:
+		keys, values := []Type{}, []Type{}
+		for _, elem := range expr.Elements {
+			keys = append(keys, c.checkExpression(elem.Key))
+			values = append(values, c.checkExpression(elem.Value))
+		}
+		typ = &Map{Key: NewUnion(keys...), Value: NewUnion(values...)}
+	case *ast.FunctionLiteral:
+		typ = c.signature(expr)
+		c.checkFunctionBody(expr)
+	case *ast.PrefixExpression:
+		typ = c.checkPrefixExpression(expr)
+	case *ast.InfixExpression:
+		typ = c.checkInfixExpression(expr)
+	case *ast.AssignExpression:
+		typ = c.checkAssignExpression(expr)
+	case *ast.CallExpression:
+		typ = c.checkCallExpression(expr)
+	case *ast.IndexExpression:
+		typ = c.checkIndexExpression(expr)
+	default:
+		typ = Any{}
+	}
+
+	c.types[expression] = typ
+	return typ
+}
+
+func (c *Checker) checkPrefixExpression(expr *ast.PrefixExpression) Type {
+	right := c.checkExpression(expr.Right)
+
+	switch expr.Operator {
+	case "!":
+		return Bool
+	case "-":
+		result, err := c.apply(right, right, func(r, _ Type) (Type, error) {
+			if r == Int || r == Float {
+				return r, nil
+			}
+			return nil, fmt.Errorf("cannot negate value of type %s", r)
+		})
+		if err != nil {
+			c.report(expr.Location(), "%s", err)
+		}
+		return result
+	default:
+		return Any{}
+	}
+}
+
+func (c *Checker) checkInfixExpression(expr *ast.InfixExpression) Type {
+	if expr.Operator == "." {
+		// module members are resolved at runtime
+		return Any{}
+	}
+
+	left := c.checkExpression(expr.Left)
+	right := c.checkExpression(expr.Right)
+
+	var rule func(l, r Type) (Type, error)
+	switch expr.Operator {
+	case "+":
+		rule = addRule
+	case "-":
+		rule = arithmeticRule("subtraction")
+	case "*":
+		rule = arithmeticRule("multiplication")
+	case "/":
+		rule = arithmeticRule("division")
+	case "<", ">", "<=", ">=":
+		rule = compareRule
+	case "|", "&":
+		// set operators, sets are only known at runtime
+		return Any{}
+	default:
+		// equality and membership are defined for every pair of values
+		return Bool
+	}
+
+	result, err := c.apply(left, right, rule)
+	if err != nil {
+		c.report(expr.Location(), "%s", err)
+	}
+
+	return result
+}
+
+func (c *Checker) checkAssignExpression(expr *ast.AssignExpression) Type {
+	right := c.checkExpression(expr.Right)
+
+	switch left := expr.Left.(type) {
+	case *ast.Identifier:
+		sym, ok := c.scope.lookup(left.Value)
+		if !ok {
+			break
+		}
+		if sym.declared {
+			if !Assignable(sym.typ, right) {
+				c.report(expr.Location(), "cannot assign %s to variable %s of type %s",
+					right, left.Value, sym.typ)
+			}
+		} else {
+			sym.typ = NewUnion(sym.typ, right)
+		}
+	case *ast.IndexExpression:
+		container := c.checkContainer(left.Left)
+		index := c.checkExpression(left.Index)
+
+		switch t := container.(type) {
+		case *Array:
+			c.checkArrayIndex(left, index)
+			if !Assignable(t.Elem, right) {
+				c.report(expr.Location(), "cannot assign %s to element of %s", right, t)
+			}
+		case *Map:
+			if !Assignable(t.Key, index) {
+				c.report(expr.Location(), "cannot use %s as key of %s", index, t)
+			}
+			if !Assignable(t.Value, right) {
+				c.report(expr.Location(), "cannot assign %s to value of %s", right, t)
+			}
+		}
+	default:
+		c.checkExpression(left)
+	}
+
+	return right
+}
+
+func (c *Checker) checkCallExpression(expr *ast.CallExpression) Type {
+	callee := c.checkExpression(expr.Callee)
+
+	args := []Type{}
+	for _, arg := range expr.Arguments {
+		args = append(args, c.checkExpression(arg))
+	}
+
+	switch fn := callee.(type) {
+	case *Function:
+		if fn.Open {
+			return Any{}
+		}
+		if len(args) != len(fn.Params) {
+			c.report(expr.Location(), "incorrect no of arguments. got=%d, expect=%d",
+				len(args), len(fn.Params))
+			return fn.Return
+		}
+		for i, arg := range args {
+			if !Assignable(fn.Params[i], arg) {
+				c.report(expr.Arguments[i].Location(), "cannot use %s as %s in argument %d",
+					arg, fn.Params[i], i+1)
+			}
+		}
+		return fn.Return
+	case Basic:
+		c.report(expr.Location(), "not a callable %s", fn)
+		return Any{}
+	default:
+		return Any{}
+	}
+}
+
+func (c *Checker) checkIndexExpression(expr *ast.IndexExpression) Type {
+	left := c.checkContainer(expr.Left)
+	index := c.checkExpression(expr.Index)
+
+	switch t := left.(type) {
+	case *Array:
+		c.checkArrayIndex(expr, index)
+		return t.Elem
+	case *Map:
+		if !Assignable(t.Key, index) {
+			c.report(expr.Location(), "cannot use %s as key of %s", index, t)
+		}
+		return NewUnion(t.Value, Null)
+	case Basic:
+		if t == String {
+			c.checkArrayIndex(expr, index)
+			return String
+		}
+		c.report(expr.Location(), "cannot index on type %s", t)
+		return Any{}
+	default:
+		return Any{}
+	}
+}
+
+// checks an indexed expression. the contents of arrays and maps held by
+// unannotated variables can change through the stdlib, so their element
+// types are only trusted when the variable has an annotation
+func (c *Checker) checkContainer(expr ast.Expression) Type {
+	typ := c.checkExpression(expr)
+
+	ident, ok := expr.(*ast.Identifier)
+	if !ok {
+		return typ
+	}
+
+	if sym, ok := c.scope.lookup(ident.Value); ok && !sym.declared {
+		switch typ.(type) {
+		case *Array, *Map:
+			return Any{}
+		}
+	}
+
+	return typ
+}
+
+func (c *Checker) checkArrayIndex(expr *ast.IndexExpression, index Type) {
+	if !Assignable(Int, index) {
+		c.report(expr.Location(), "expect integer index, got=%s", index)
+	}
+}
+
+// builds the function type described by a literal's annotations
+func (c *Checker) signature(fn *ast.FunctionLiteral) *Function {
+	sig := &Function{Return: FromAST(fn.ReturnType)}
+	for i := range fn.Parameters {
+		var annotation ast.TypeExpr
+		if i < len(fn.ParamTypes) {
+			annotation = fn.ParamTypes[i]
+		}
+		sig.Params = append(sig.Params, FromAST(annotation))
+	}
+
+	return sig
+}
+
+func (c *Checker) checkFunctionBody(fn *ast.FunctionLiteral) {
+	sig := c.signature(fn)
+
+	outerReturns := c.returns
+	c.returns = sig.Return
+	c.scope = newScope(c.scope)
+
+	for i, param := range fn.Parameters {
+		c.declare(param.Location(), param.Value, &symbol{
+			typ:      sig.Params[i],
+			declared: i < len(fn.ParamTypes) && fn.ParamTypes[i] != nil,
+		})
+	}
+
+	if fn.Body != nil {
+		c.checkStatements(fn.Body.Statements)
+	}
+
+	c.scope = c.scope.outer
+	c.returns = outerReturns
+}
+
+func (c *Checker) declare(loc token.SrcLoc, name string, sym *symbol) {
+	if _, ok := c.scope.symbols[name]; ok {
+		c.report(loc, "variable %s already exists in current scope", name)
+		return
+	}
+
+	c.scope.symbols[name] = sym
+}
+
+// apply runs an operator rule over every combination of the operand
+// types. the operation is only rejected if no combination is valid,
+// otherwise the result is the union of all valid outcomes
+func (c *Checker) apply(left, right Type, rule func(l, r Type) (Type, error)) (Type, error) {
+	if _, ok := left.(Any); ok {
+		return Any{}, nil
+	}
+	if _, ok := right.(Any); ok {
+		return Any{}, nil
+	}
+
+	var firstErr error
+	results := []Type{}
+	for _, l := range members(left) {
+		for _, r := range members(right) {
+			result, err := rule(l, r)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		return Any{}, firstErr
+	}
+
+	return NewUnion(results...), nil
+}
+
+func isNumeric(typ Type) bool {
+	return typ == Int || typ == Float
+}
+
+func isArray(typ Type) bool {
+	_, ok := typ.(*Array)
+	return ok
+}
+
+// mirrors the promotion rules of the evaluator's addition operator
+func addRule(l, r Type) (Type, error) {
+	switch {
+	case l == Int && r == Int:
+		return Int, nil
+	case isNumeric(l) && isNumeric(r):
+		return Float, nil
+	case l == String && (r == String || isNumeric(r)):
+		return String, nil
+	case isNumeric(l) && r == String:
+		return String, nil
+	}
+
+	if la, ok := l.(*Array); ok {
+		if ra, ok := r.(*Array); ok {
+			return &Array{Elem: NewUnion(la.Elem, ra.Elem)}, nil
+		}
+	}
+
+	if lm, ok := l.(*Map); ok {
+		if rm, ok := r.(*Map); ok {
+			return &Map{Key: NewUnion(lm.Key, rm.Key), Value: NewUnion(lm.Value, rm.Value)}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("addition not supported for %s and %s", l, r)
+}
+
+func arithmeticRule(name string) func(l, r Type) (Type, error) {
+	return func(l, r Type) (Type, error) {
+		switch {
+		case l == Int && r == Int:
+			return Int, nil
+		case isNumeric(l) && isNumeric(r):
+			return Float, nil
+		default:
+			return nil, fmt.Errorf("%s not supported for %s and %s", name, l, r)
+		}
+	}
+}
+
+func compareRule(l, r Type) (Type, error) {
+	switch {
+	case isNumeric(l) && isNumeric(r):
+		return Bool, nil
+	case l == String && r == String:
+		return Bool, nil
+	case isArray(l) && isArray(r):
+		// arrays are ordered by their elements, which
+		// are only known to be comparable at runtime
+		return Bool, nil
+	default:
+		return nil, fmt.Errorf("cannot compare types %s and %s", l, r)
+	}
+}
+
+func (c *Checker) report(loc token.SrcLoc, format string, args ...any) {
+	err := fmt.Errorf("%s %s", loc, fmt.Sprintf(format, args...))
+	c.errors = append(c.errors, err)
+}
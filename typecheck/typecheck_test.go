@@ -0,0 +1,93 @@
+package typecheck
+
+import (
+	"RoLang/ast"
+	"RoLang/lexer"
+	"RoLang/parser"
+
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []string
+	}{
+		{"let x = 1 + 2; let y = x * 2.5;", nil},
+		{"let x = 1 + true;", []string{"addition not supported for int and bool"}},
+		{`let x: int = "a";`, []string{"cannot use string as type int in let x"}},
+		{"let x: float = 1;", nil},
+		{"let x: int | string = 1; x = \"a\"; x = true;",
+			[]string{"cannot assign bool to variable x of type int | string"}},
+		{`let x = 1; x = "a"; let y = x - 1;`, nil},
+		{`let x = "a"; let y = x - 1;`, []string{"subtraction not supported for string and int"}},
+		{"fn add(x: int, y: int) -> int { return x + y; } add(1, 2.5);",
+			[]string{"cannot use float as int in argument 2"}},
+		{"fn add(x: int, y: int) -> int { return x + y; } add(1);",
+			[]string{"incorrect no of arguments. got=1, expect=2"}},
+		{`fn name() -> string { return 1; }`, []string{"cannot return int from function returning string"}},
+		{"fn f(x) { return x + true; }", nil},
+		{"let a: [int] = [1, 2]; a[0] = \"b\";", []string{"cannot assign string to element of [int]"}},
+		{"let a = [1, 2]; a[0] = \"b\";", nil},
+		{`let m: {string: int} = {"a": 1, "b": true};`,
+			[]string{"cannot use {string: int | bool} as type {string: int} in let m"}},
+		{"let x = 1; x();", []string{"not a callable int"}},
+		{"return 1.5;", []string{"can only return integer exit codes at top level, got float"}},
+		{"let f: fn(int) -> int = fn(x: int) -> int { return x; }; let y: string = f(1);",
+			[]string{"cannot use int as type string in let y"}},
+		{"let s = sets.new([1]) | sets.new([2]); let b: bool = 1 in s;", nil},
+		{"let b: int = 1 in [1];", []string{"cannot use bool as type int in let b"}},
+		{"let b: bool = [1, 2] < [1, 3];", nil},
+		{"let b = [1] < 1;", []string{"cannot compare types [int] and int"}},
+		{"let s: set = sets.new(); let r: regex = regex.compile(\"a\");", nil},
+		{"let s: set = 1;", []string{"cannot use int as type set in let s"}},
+	}
+
+	for i, test := range tests {
+		l := lexer.New("typecheck_test", test.input)
+		p := parser.New(l)
+
+		program, errs := p.Parse()
+		if len(errs) != 0 {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		errs = New().Check(program)
+		if len(errs) != len(test.expect) {
+			t.Errorf("test[%d] wrong number of errors. got=%d, expect=%d (%v)",
+				i, len(errs), len(test.expect), errs)
+			continue
+		}
+
+		for j, err := range errs {
+			if !strings.HasSuffix(err.Error(), test.expect[j]) {
+				t.Errorf("test[%d] wrong error. got=%q, expect=%q", i, err, test.expect[j])
+			}
+		}
+	}
+}
+
+func TestInference(t *testing.T) {
+	input := `let a = [1, 2.5]; let b = {"k": a}; let c = 1 + "s"; let d = a[0];`
+
+	l := lexer.New("typecheck_test", input)
+	p := parser.New(l)
+
+	program, errs := p.Parse()
+	if len(errs) != 0 {
+		t.Fatal(errors.Join(errs...))
+	}
+
+	checker := New()
+	checker.Check(program)
+
+	expects := []string{"[int | float]", "{string: [int | float]}", "string", "any"}
+	for i, expect := range expects {
+		init := program.Statements[i].(*ast.LetStatement).InitValue
+		if typ := checker.TypeOf(init); typ.String() != expect {
+			t.Errorf("test[%d] wrong type. got=%s, expect=%s", i, typ, expect)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package typecheck
+
+import (
+	"RoLang/ast"
+
+	"fmt"
+	"slices"
+)
+
+type Type interface {
+	String() string
+}
+
+type (
+	// int, float, string, bool, null and the types of
+	// stdlib values, like set, file or regex
+	Basic string
+
+	// matches every value, used wherever a type cannot be inferred
+	Any struct{}
+
+	Array struct {
+		Elem Type
+	}
+
+	Map struct {
+		Key   Type
+		Value Type
+	}
+
+	Union struct {
+		Types []Type
+	}
+
+	Function struct {
+		Params []Type
+		Return Type
+		Open   bool // plain `function` annotation, parameters are unknown
+	}
+)
+
+var (
+	Int    = Basic("int")
+	Float  = Basic("float")
+	String = Basic("string")
+	Bool   = Basic("bool")
+	Null   = Basic("null")
+)
+
+func (b Basic) String() string {
+	return string(b)
+}
+
+func (Any) String() string {
+	return "any"
+}
+
+func (a *Array) String() string {
+	return fmt.Sprintf("[%s]", a.Elem)
+}
+
+func (m *Map) String() string {
+	return fmt.Sprintf("{%s: %s}", m.Key, m.Value)
+}
+
+func (u *Union) String() string {
+	var out string
+	for i, typ := range u.Types {
+		if i == 0 {
+			out += typ.String()
+		} else {
+			out += " | " + typ.String()
+		}
+	}
+
+	return out
+}
+
+func (f *Function) String() string {
+	if f.Open {
+		return "function"
+	}
+
+	var params string
+	for i, param := range f.Params {
+		if i == 0 {
+			params += param.String()
+		} else {
+			params += ", " + param.String()
+		}
+	}
+
+	return fmt.Sprintf("fn(%s) -> %s", params, f.Return)
+}
+
+// converts a parsed annotation into a checker type
+// a missing annotation is treated as `any`
+func FromAST(expr ast.TypeExpr) Type {
+	switch t := expr.(type) {
+	case nil:
+		return Any{}
+	case *ast.NamedType:
+		switch t.Name {
+		case "any":
+			return Any{}
+		case "function":
+			return &Function{Open: true, Return: Any{}}
+		default:
+			return Basic(t.Name)
+		}
+	case *ast.ArrayType:
+		return &Array{Elem: FromAST(t.Elem)}
+	case *ast.MapType:
+		return &Map{Key: FromAST(t.Key), Value: FromAST(t.Value)}
+	case *ast.UnionType:
+		types := []Type{}
+		for _, typ := range t.Types {
+			types = append(types, FromAST(typ))
+		}
+		return NewUnion(types...)
+	case *ast.FunctionType:
+		fn := &Function{Return: FromAST(t.ReturnType)}
+		for _, param := range t.Params {
+			fn.Params = append(fn.Params, FromAST(param))
+		}
+		if t.ReturnType == nil {
+			fn.Return = Null
+		}
+		return fn
+	default:
+		return Any{}
+	}
+}
+
+// builds a flattened union without duplicates, collapsing
+// to a single type or `any` where possible
+func NewUnion(types ...Type) Type {
+	members := []Type{}
+	seen := map[string]bool{}
+
+	var add func(Type)
+	add = func(typ Type) {
+		switch t := typ.(type) {
+		case nil:
+			return
+		case Any:
+			seen["any"] = true
+		case *Union:
+			for _, member := range t.Types {
+				add(member)
+			}
+		default:
+			if !seen[t.String()] {
+				seen[t.String()] = true
+				members = append(members, t)
+			}
+		}
+	}
+
+	for _, typ := range types {
+		add(typ)
+	}
+
+	switch {
+	case seen["any"]:
+		return Any{}
+	case len(members) == 0:
+		return Any{}
+	case len(members) == 1:
+		return members[0]
+	default:
+		return &Union{Types: members}
+	}
+}
+
+// members returns the alternatives a type can take
+func members(typ Type) []Type {
+	if u, ok := typ.(*Union); ok {
+		return u.Types
+	}
+
+	return []Type{typ}
+}
+
+// reports if a value of type `from` can be stored where `to` is expected
+// ints are accepted where floats are expected, just like at runtime
+func Assignable(to, from Type) bool {
+	if _, ok := to.(Any); ok {
+		return true
+	}
+	if _, ok := from.(Any); ok {
+		return true
+	}
+
+	if u, ok := from.(*Union); ok {
+		for _, member := range u.Types {
+			if !Assignable(to, member) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if u, ok := to.(*Union); ok {
+		return slices.ContainsFunc(u.Types, func(member Type) bool {
+			return Assignable(member, from)
+		})
+	}
+
+	switch t := to.(type) {
+	case Basic:
+		f, ok := from.(Basic)
+		return ok && (t == f || t == Float && f == Int)
+	case *Array:
+		f, ok := from.(*Array)
+		return ok && Assignable(t.Elem, f.Elem)
+	case *Map:
+		f, ok := from.(*Map)
+		return ok && Assignable(t.Key, f.Key) && Assignable(t.Value, f.Value)
+	case *Function:
+		f, ok := from.(*Function)
+		if !ok {
+			return false
+		}
+		if t.Open || f.Open {
+			return true
+		}
+		if len(t.Params) != len(f.Params) {
+			return false
+		}
+		for i := range t.Params {
+			// parameters are contravariant
+			if !Assignable(f.Params[i], t.Params[i]) {
+				return false
+			}
+		}
+		return Assignable(t.Return, f.Return)
+	default:
+		return false
+	}
+}